@@ -0,0 +1,251 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	AlarmResourceDdb            = "ddb"
+	AlarmResourceHttpRoute      = "http_route"
+	AlarmResourceKinsumer       = "kinsumer"
+	AlarmResourceSqs            = "sqs"
+	AlarmResourceStreamConsumer = "stream_consumer"
+)
+
+const alarmComparisonGreaterThanThreshold = "GreaterThanThreshold"
+
+var defaultAlarmSettings = map[string]AlarmSettings{
+	AlarmResourceDdb:            {Enabled: true, Threshold: 0, Period: 300, EvaluationPeriods: 1},
+	AlarmResourceHttpRoute:      {Enabled: true, Threshold: 10, Period: 300, EvaluationPeriods: 1},
+	AlarmResourceKinsumer:       {Enabled: true, Threshold: 300000, Period: 60, EvaluationPeriods: 5},
+	AlarmResourceSqs:            {Enabled: true, Threshold: 1000, Period: 300, EvaluationPeriods: 3},
+	AlarmResourceStreamConsumer: {Enabled: true, Threshold: 0, Period: 300, EvaluationPeriods: 1},
+}
+
+// AlarmSettings configure the alarms of a resource type, an alarm fires if the metric is above the threshold for
+// all evaluation periods
+type AlarmSettings struct {
+	Enabled           bool
+	Threshold         float64
+	Period            int
+	EvaluationPeriods int
+}
+
+// AlarmDefinition describes a cloudwatch metric alarm, it has the fields of an aws_cloudwatch_metric_alarm
+type AlarmDefinition struct {
+	Name               string
+	Namespace          string
+	MetricName         string
+	Dimensions         map[string]string
+	Statistic          string
+	Period             int
+	EvaluationPeriods  int
+	Threshold          float64
+	ComparisonOperator string
+}
+
+type AlarmDefinitions []AlarmDefinition
+
+// AlarmBuilder derives the alarms of an application from the resources listed in its metadata
+type AlarmBuilder struct {
+	resourceNames *ResourceNames
+	filter        *DashboardFilter
+	prefix        string
+	settings      map[string]AlarmSettings
+	alarms        AlarmDefinitions
+}
+
+func AvailableAlarmResources() []string {
+	return sortedKeys(defaultAlarmSettings)
+}
+
+// DefaultAlarmSettings returns the settings of the resource type which are used unless configured otherwise
+func DefaultAlarmSettings(resource string) AlarmSettings {
+	return defaultAlarmSettings[resource]
+}
+
+// NewAlarmBuilder creates a builder with the given settings per resource type, the other types keep their defaults.
+// Routes which are not included by the filter get no alarms, a nil filter keeps all of them.
+func NewAlarmBuilder(resourceNames *ResourceNames, appId AppId, settings map[string]AlarmSettings, filter *DashboardFilter) (*AlarmBuilder, error) {
+	merged := make(map[string]AlarmSettings, len(defaultAlarmSettings))
+	for resource, defaults := range defaultAlarmSettings {
+		merged[resource] = defaults
+	}
+
+	for resource, resourceSettings := range settings {
+		if _, ok := defaultAlarmSettings[resource]; !ok {
+			return nil, fmt.Errorf("'%s' is not a valid alarm resource, choose between %v", resource, AvailableAlarmResources())
+		}
+
+		if resourceSettings.Enabled && (resourceSettings.Period <= 0 || resourceSettings.EvaluationPeriods <= 0) {
+			return nil, fmt.Errorf("the period and evaluation periods of the %s alarms have to be positive, got %d and %d", resource, resourceSettings.Period, resourceSettings.EvaluationPeriods)
+		}
+
+		merged[resource] = resourceSettings
+	}
+
+	return &AlarmBuilder{
+		resourceNames: resourceNames,
+		filter:        filter,
+		prefix:        Augment("{project}-{env}-{family}-{group}-{app}", appId),
+		settings:      merged,
+		alarms:        make(AlarmDefinitions, 0),
+	}, nil
+}
+
+func (b *AlarmBuilder) AddCloudAwsSqsQueue(queue MetadataCloudAwsSqsQueue) {
+	b.addAlarm(AlarmResourceSqs, []string{queue.QueueName, "messages-visible"}, AlarmDefinition{
+		Namespace:  "AWS/SQS",
+		MetricName: "ApproximateNumberOfMessagesVisible",
+		Dimensions: map[string]string{
+			"QueueName": queue.QueueNameFull,
+		},
+		Statistic: "Maximum",
+	})
+}
+
+func (b *AlarmBuilder) AddCloudAwsKinesisKinsumer(stream MetadataCloudAwsKinesisKinsumer) {
+	b.addAlarm(AlarmResourceKinsumer, []string{stream.StreamName, "milliseconds-behind"}, AlarmDefinition{
+		Namespace:  b.resourceNames.CloudwatchNamespace,
+		MetricName: "MillisecondsBehind",
+		Dimensions: map[string]string{
+			"StreamName": stream.StreamNameFull,
+		},
+		Statistic: "Maximum",
+	})
+}
+
+// AddDynamoDbTable adds an alarm for the read and one for the write throttle events of the table
+func (b *AlarmBuilder) AddDynamoDbTable(table MetadataCloudAwsDynamodbTable) {
+	for _, metric := range []struct {
+		name   string
+		suffix string
+	}{
+		{name: "ReadThrottleEvents", suffix: "read-throttles"},
+		{name: "WriteThrottleEvents", suffix: "write-throttles"},
+	} {
+		b.addAlarm(AlarmResourceDdb, []string{table.TableName, metric.suffix}, AlarmDefinition{
+			Namespace:  "AWS/DynamoDB",
+			MetricName: metric.name,
+			Dimensions: map[string]string{
+				"TableName": table.TableName,
+			},
+			Statistic: "Sum",
+		})
+	}
+}
+
+func (b *AlarmBuilder) AddStreamConsumer(consumer MetadataStreamConsumer) {
+	b.addAlarm(AlarmResourceStreamConsumer, []string{consumer.Name, "errors"}, AlarmDefinition{
+		Namespace:  b.resourceNames.CloudwatchNamespace,
+		MetricName: "Error",
+		Dimensions: map[string]string{
+			"Consumer": consumer.Name,
+		},
+		Statistic: "Sum",
+	})
+}
+
+func (b *AlarmBuilder) AddHttpServerHandler(serverName string, handler MetadataHttpServerHandler) {
+	if !b.filter.IncludesRoute(handler) {
+		return
+	}
+
+	b.addAlarm(AlarmResourceHttpRoute, []string{serverName, handler.Method, handler.Path, "5xx"}, AlarmDefinition{
+		Namespace:  b.resourceNames.CloudwatchNamespace,
+		MetricName: "HttpStatus5XXPerRoute",
+		Dimensions: map[string]string{
+			"Method":     handler.Method,
+			"Path":       handler.Path,
+			"ServerName": serverName,
+		},
+		Statistic: "Sum",
+	})
+}
+
+// Build returns the alarms sorted by their name
+func (b *AlarmBuilder) Build() AlarmDefinitions {
+	alarms := append(AlarmDefinitions{}, b.alarms...)
+
+	sort.Slice(alarms, func(i, j int) bool {
+		return alarms[i].Name < alarms[j].Name
+	})
+
+	return alarms
+}
+
+// addAlarm completes the alarm with the settings of its resource type, alarms of disabled resource types are skipped
+func (b *AlarmBuilder) addAlarm(resource string, nameParts []string, alarm AlarmDefinition) {
+	settings := b.settings[resource]
+	if !settings.Enabled {
+		return
+	}
+
+	alarm.Name = strings.Join(append([]string{b.prefix, resource}, nameParts...), "-")
+	alarm.Period = settings.Period
+	alarm.EvaluationPeriods = settings.EvaluationPeriods
+	alarm.Threshold = settings.Threshold
+	alarm.ComparisonOperator = alarmComparisonGreaterThanThreshold
+
+	b.alarms = append(b.alarms, alarm)
+}
+
+func (a AlarmDefinitions) ToValue() types.List {
+	list := types.List{
+		Elems: make([]attr.Value, len(a)),
+		ElemType: types.ObjectType{
+			AttrTypes: AlarmDefinitionAttrTypes(),
+		},
+	}
+
+	for i, alarm := range a {
+		list.Elems[i] = alarm.ToValue()
+	}
+
+	return list
+}
+
+func (a AlarmDefinition) ToValue() attr.Value {
+	dimensions := types.Map{
+		Elems:    make(map[string]attr.Value, len(a.Dimensions)),
+		ElemType: types.StringType,
+	}
+
+	for name, value := range a.Dimensions {
+		dimensions.Elems[name] = types.String{Value: value}
+	}
+
+	return types.Object{
+		Attrs: map[string]attr.Value{
+			"name":                types.String{Value: a.Name},
+			"namespace":           types.String{Value: a.Namespace},
+			"metric_name":         types.String{Value: a.MetricName},
+			"dimensions":          dimensions,
+			"statistic":           types.String{Value: a.Statistic},
+			"period":              types.Int64{Value: int64(a.Period)},
+			"evaluation_periods":  types.Int64{Value: int64(a.EvaluationPeriods)},
+			"threshold":           types.Float64{Value: a.Threshold},
+			"comparison_operator": types.String{Value: a.ComparisonOperator},
+		},
+		AttrTypes: AlarmDefinitionAttrTypes(),
+	}
+}
+
+func AlarmDefinitionAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"name":                types.StringType,
+		"namespace":           types.StringType,
+		"metric_name":         types.StringType,
+		"dimensions":          types.MapType{ElemType: types.StringType},
+		"statistic":           types.StringType,
+		"period":              types.Int64Type,
+		"evaluation_periods":  types.Int64Type,
+		"threshold":           types.Float64Type,
+		"comparison_operator": types.StringType,
+	}
+}
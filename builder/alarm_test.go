@@ -0,0 +1,88 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlarmBuilder(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		CloudwatchNamespace: "prj/env/fam/grp-app",
+	}
+
+	ab, err := builder.NewAlarmBuilder(resourceNames, provideAppId(), map[string]builder.AlarmSettings{
+		builder.AlarmResourceSqs:       {Enabled: true, Threshold: 50, Period: 60, EvaluationPeriods: 2},
+		builder.AlarmResourceHttpRoute: {Enabled: false},
+	}, nil)
+	assert.NoError(t, err)
+
+	ab.AddCloudAwsSqsQueue(builder.MetadataCloudAwsSqsQueue{QueueName: "orders", QueueNameFull: "prj-env-fam-grp-app-orders"})
+	ab.AddDynamoDbTable(builder.MetadataCloudAwsDynamodbTable{TableName: "prj-env-fam-grp-app-items"})
+	ab.AddCloudAwsKinesisKinsumer(builder.MetadataCloudAwsKinesisKinsumer{StreamName: "events", StreamNameFull: "prj-env-fam-grp-events"})
+	ab.AddHttpServerHandler("default", builder.MetadataHttpServerHandler{Method: "GET", Path: "/v1/orders"})
+	alarms := ab.Build()
+
+	names := make([]string, len(alarms))
+	for i, alarm := range alarms {
+		names[i] = alarm.Name
+	}
+
+	assert.Equal(t, []string{
+		"prj-env-fam-grp-app-ddb-prj-env-fam-grp-app-items-read-throttles",
+		"prj-env-fam-grp-app-ddb-prj-env-fam-grp-app-items-write-throttles",
+		"prj-env-fam-grp-app-kinsumer-events-milliseconds-behind",
+		"prj-env-fam-grp-app-sqs-orders-messages-visible",
+	}, names)
+
+	assert.Equal(t, builder.AlarmDefinition{
+		Name:               "prj-env-fam-grp-app-sqs-orders-messages-visible",
+		Namespace:          "AWS/SQS",
+		MetricName:         "ApproximateNumberOfMessagesVisible",
+		Dimensions:         map[string]string{"QueueName": "prj-env-fam-grp-app-orders"},
+		Statistic:          "Maximum",
+		Period:             60,
+		EvaluationPeriods:  2,
+		Threshold:          50,
+		ComparisonOperator: "GreaterThanThreshold",
+	}, alarms[3])
+
+	kinsumer := alarms[2]
+	assert.Equal(t, "prj/env/fam/grp-app", kinsumer.Namespace)
+	assert.Equal(t, builder.DefaultAlarmSettings(builder.AlarmResourceKinsumer).Threshold, kinsumer.Threshold)
+}
+
+func TestNewAlarmBuilderErrors(t *testing.T) {
+	_, err := builder.NewAlarmBuilder(&builder.ResourceNames{}, provideAppId(), map[string]builder.AlarmSettings{
+		"fancy": {},
+	}, nil)
+	assert.ErrorContains(t, err, "'fancy' is not a valid alarm resource")
+
+	_, err = builder.NewAlarmBuilder(&builder.ResourceNames{}, provideAppId(), map[string]builder.AlarmSettings{
+		builder.AlarmResourceSqs: {Enabled: true, Threshold: 10},
+	}, nil)
+	assert.EqualError(t, err, "the period and evaluation periods of the sqs alarms have to be positive, got 0 and 0")
+}
+
+func TestAlarmBuilderRouteFilter(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		CloudwatchNamespace: "prj/env/fam/grp-app",
+	}
+
+	filter, err := builder.NewDashboardFilter(builder.DashboardFilterSettings{
+		RoutePaths: builder.NameFilterSettings{Exclude: []string{"^/health$"}},
+	})
+	assert.NoError(t, err)
+
+	ab, err := builder.NewAlarmBuilder(resourceNames, provideAppId(), nil, filter)
+	assert.NoError(t, err)
+
+	ab.AddHttpServerHandler("default", builder.MetadataHttpServerHandler{Method: "GET", Path: "/health"})
+	ab.AddHttpServerHandler("default", builder.MetadataHttpServerHandler{Method: "GET", Path: "/v1/orders"})
+	alarms := ab.Build()
+
+	if assert.Len(t, alarms, 1) {
+		assert.Equal(t, "/v1/orders", alarms[0].Dimensions["Path"])
+	}
+}
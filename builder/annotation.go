@@ -0,0 +1,44 @@
+package builder
+
+const (
+	defaultAnnotationIconColor = "#5794F2"
+	annotationStep             = "60s"
+)
+
+type DashboardAnnotations struct {
+	List []DashboardAnnotation `json:"list"`
+}
+
+// DashboardAnnotation is a prometheus annotation query, every series returned by the expression marks an event
+type DashboardAnnotation struct {
+	Datasource  string `json:"datasource"`
+	Enable      bool   `json:"enable"`
+	Expression  string `json:"expr"`
+	Hide        bool   `json:"hide"`
+	IconColor   string `json:"iconColor"`
+	Name        string `json:"name"`
+	Step        string `json:"step"`
+	TagKeys     string `json:"tagKeys,omitempty"`
+	TextFormat  string `json:"textFormat,omitempty"`
+	TitleFormat string `json:"titleFormat"`
+}
+
+type DashboardAnnotationSettings struct {
+	// Deployments marks the deployments of the application, the query depends on the orchestrator
+	Deployments bool
+	// IconColor is the color of the annotations, grafana's blue if empty
+	IconColor string
+}
+
+func NewDeploymentAnnotation(expression string, textFormat string) DashboardAnnotation {
+	return DashboardAnnotation{
+		Datasource:  datasourcePrometheus,
+		Enable:      true,
+		Expression:  expression,
+		IconColor:   defaultAnnotationIconColor,
+		Name:        "Deployments",
+		Step:        annotationStep,
+		TextFormat:  textFormat,
+		TitleFormat: "Deployment",
+	}
+}
@@ -0,0 +1,91 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeploymentAnnotation(t *testing.T) {
+	tests := []struct {
+		orchestrator string
+		workloadKind string
+		expression   string
+		textFormat   string
+	}{
+		{
+			orchestrator: "ecs",
+			expression:   `count by (container_label_com_amazonaws_ecs_task_definition_version) (container_last_seen{container_label_com_amazonaws_ecs_cluster="cluster", container_label_com_amazonaws_ecs_task_definition_family="task-def"}) unless count by (container_label_com_amazonaws_ecs_task_definition_version) (container_last_seen{container_label_com_amazonaws_ecs_cluster="cluster", container_label_com_amazonaws_ecs_task_definition_family="task-def"} offset 5m)`,
+			textFormat:   "task-def revision {{container_label_com_amazonaws_ecs_task_definition_version}}",
+		},
+		{
+			orchestrator: "kubernetes",
+			workloadKind: builder.KubernetesWorkloadKindDeployment,
+			expression:   `changes(kube_deployment_status_observed_generation{namespace="prj", deployment="grp-app"}[2m]) > 0`,
+			textFormat:   "Deployment prj/grp-app",
+		},
+		{
+			orchestrator: "kubernetes",
+			workloadKind: builder.KubernetesWorkloadKindStatefulSet,
+			expression:   `changes(kube_statefulset_status_observed_generation{namespace="prj", statefulset="grp-app"}[2m]) > 0`,
+			textFormat:   "StatefulSet prj/grp-app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.orchestrator+tt.workloadKind, func(t *testing.T) {
+			resourceNames := &builder.ResourceNames{
+				EcsCluster:             "cluster",
+				EcsTaskDefinition:      "task-def",
+				KubernetesNamespace:    "prj",
+				KubernetesDeployment:   "grp-app",
+				KubernetesPod:          "grp-app",
+				KubernetesWorkloadKind: tt.workloadKind,
+				Containers:             []string{"app"},
+			}
+
+			dashboard := newDashboardBuilder(t, resourceNames, tt.orchestrator).Build("")
+
+			assert.Equal(t, &builder.DashboardAnnotations{
+				List: []builder.DashboardAnnotation{
+					{
+						Datasource:  "prometheus",
+						Enable:      true,
+						Expression:  tt.expression,
+						IconColor:   "#5794F2",
+						Name:        "Deployments",
+						Step:        "60s",
+						TextFormat:  tt.textFormat,
+						TitleFormat: "Deployment",
+					},
+				},
+			}, dashboard.Annotations)
+		})
+	}
+}
+
+func TestDeploymentAnnotationSettings(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		EcsCluster:        "cluster",
+		EcsTaskDefinition: "task-def",
+		Containers:        []string{"app"},
+	}
+
+	dashboard := newDashboardBuilder(t, resourceNames, "ecs", builder.WithAnnotations(builder.DashboardAnnotationSettings{})).Build("")
+	assert.Nil(t, dashboard.Annotations)
+
+	dashboard = newDashboardBuilder(t, resourceNames, "ecs_fargate").Build("")
+	assert.Nil(t, dashboard.Annotations)
+
+	dashboard = newDashboardBuilder(t,
+		resourceNames,
+		"ecs",
+		builder.WithTemplating(),
+		builder.WithAnnotations(builder.DashboardAnnotationSettings{Deployments: true, IconColor: "red"}),
+	).Build("")
+
+	assert.Len(t, dashboard.Annotations.List, 1)
+	assert.Equal(t, "red", dashboard.Annotations.List[0].IconColor)
+	assert.Equal(t, "${datasource_prometheus}", dashboard.Annotations.List[0].Datasource)
+}
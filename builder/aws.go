@@ -0,0 +1,76 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+type AwsSettings struct {
+	Region                 string
+	Profile                string
+	SharedConfigFiles      []string
+	SharedCredentialsFiles []string
+	AssumeRole             *AwsAssumeRoleSettings
+	Endpoints              AwsEndpointSettings
+}
+
+type AwsAssumeRoleSettings struct {
+	RoleArn     string
+	ExternalId  string
+	SessionName string
+}
+
+type AwsEndpointSettings struct {
+	Ecs   string
+	Elbv2 string
+}
+
+func LoadAwsConfig(ctx context.Context, settings AwsSettings) (aws.Config, error) {
+	var err error
+	var cfg aws.Config
+
+	opts := make([]func(*config.LoadOptions) error, 0)
+
+	if settings.Region != "" {
+		opts = append(opts, config.WithRegion(settings.Region))
+	}
+
+	if settings.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(settings.Profile))
+	}
+
+	if len(settings.SharedConfigFiles) > 0 {
+		opts = append(opts, config.WithSharedConfigFiles(settings.SharedConfigFiles))
+	}
+
+	if len(settings.SharedCredentialsFiles) > 0 {
+		opts = append(opts, config.WithSharedCredentialsFiles(settings.SharedCredentialsFiles))
+	}
+
+	if cfg, err = config.LoadDefaultConfig(ctx, opts...); err != nil {
+		return cfg, fmt.Errorf("unable to load SDK config, %w", err)
+	}
+
+	if settings.AssumeRole == nil || settings.AssumeRole.RoleArn == "" {
+		return cfg, nil
+	}
+
+	assumeRole := *settings.AssumeRole
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), assumeRole.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+		if assumeRole.ExternalId != "" {
+			o.ExternalID = aws.String(assumeRole.ExternalId)
+		}
+
+		if assumeRole.SessionName != "" {
+			o.RoleSessionName = assumeRole.SessionName
+		}
+	})
+	cfg.Credentials = aws.NewCredentialsCache(provider)
+
+	return cfg, nil
+}
@@ -0,0 +1,504 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbTypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/cenkalti/backoff/v4"
+)
+
+const (
+	// ecs allows at most 10 services per DescribeServices call
+	ecsDescribeServicesBatchSize = 10
+	// time to wait for further services of the same cluster before a batch is sent
+	ecsDescribeServicesBatchWindow = 50 * time.Millisecond
+	// time a batch of services may take, including the retries of throttled calls
+	ecsDescribeServicesTimeout = 5 * time.Minute
+	// elbv2 allows at most 20 resources per DescribeTags call
+	elbDescribeTagsBatchSize = 20
+)
+
+type AwsClientsOpt func(bo *backoff.ExponentialBackOff)
+
+// AwsClients lazily builds the aws clients once per provider and caches the ecs services described through it,
+// so every service is only described once per plan no matter how many data sources are referring to it. Failed
+// descriptions aren't cached, a later read describes the service again.
+type AwsClients struct {
+	settings       AwsSettings
+	backoffFactory BackoffFactory
+
+	initOnce sync.Once
+	initErr  error
+	ecsSvc   *ecs.Client
+	elbSvc   *elasticloadbalancingv2.Client
+
+	lck                sync.Mutex
+	ecsServices        map[string]*ecsServiceResult
+	ecsTaskDefinitions map[string]*ecsTaskDefinitionResult
+	pendingServices    map[string]*ecsServiceBatch
+	elbTargetGroups    *elbTargetGroupsResult
+}
+
+// ecsServiceBatch collects the services of a cluster which are described together. The batch is shared by several
+// reads, so it is sent with a context detached from their cancellation, each read only stops waiting for it.
+type ecsServiceBatch struct {
+	ctx          context.Context
+	serviceNames []string
+}
+
+type ecsServiceResult struct {
+	done    chan struct{}
+	service *ecsTypes.Service
+	err     error
+}
+
+type ecsTaskDefinitionResult struct {
+	done           chan struct{}
+	taskDefinition *ecsTypes.TaskDefinition
+	err            error
+}
+
+// elbTargetGroupsResult contains all target groups of the account together with their tags
+type elbTargetGroupsResult struct {
+	done         chan struct{}
+	targetGroups []taggedElbTargetGroup
+	err          error
+}
+
+type taggedElbTargetGroup struct {
+	targetGroup elbTypes.TargetGroup
+	tags        map[string]string
+}
+
+func NewAwsClients(settings AwsSettings, opts ...AwsClientsOpt) *AwsClients {
+	bof := func() *backoff.ExponentialBackOff {
+		bo := backoff.NewExponentialBackOff()
+		bo.InitialInterval = time.Second
+		bo.MaxInterval = time.Second * 30
+		bo.MaxElapsedTime = time.Minute * 5
+
+		for _, opt := range opts {
+			opt(bo)
+		}
+
+		return bo
+	}
+
+	return &AwsClients{
+		settings:           settings,
+		backoffFactory:     bof,
+		ecsServices:        make(map[string]*ecsServiceResult),
+		ecsTaskDefinitions: make(map[string]*ecsTaskDefinitionResult),
+		pendingServices:    make(map[string]*ecsServiceBatch),
+	}
+}
+
+func (c *AwsClients) init(ctx context.Context) error {
+	c.initOnce.Do(func() {
+		var cfg aws.Config
+
+		if cfg, c.initErr = LoadAwsConfig(ctx, c.settings); c.initErr != nil {
+			return
+		}
+
+		c.ecsSvc = ecs.NewFromConfig(cfg, func(o *ecs.Options) {
+			if c.settings.Endpoints.Ecs != "" {
+				o.BaseEndpoint = aws.String(c.settings.Endpoints.Ecs)
+			}
+		})
+		c.elbSvc = elasticloadbalancingv2.NewFromConfig(cfg, func(o *elasticloadbalancingv2.Options) {
+			if c.settings.Endpoints.Elbv2 != "" {
+				o.BaseEndpoint = aws.String(c.settings.Endpoints.Elbv2)
+			}
+		})
+	})
+
+	return c.initErr
+}
+
+func (c *AwsClients) Ecs(ctx context.Context) (*ecs.Client, error) {
+	if err := c.init(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.ecsSvc, nil
+}
+
+func (c *AwsClients) Elbv2(ctx context.Context) (*elasticloadbalancingv2.Client, error) {
+	if err := c.init(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.elbSvc, nil
+}
+
+// DescribeEcsService returns the cached description of the service. Services of the same cluster which are requested
+// at roughly the same time are described together in a single DescribeServices call.
+func (c *AwsClients) DescribeEcsService(ctx context.Context, clusterName, serviceName string) (*ecsTypes.Service, error) {
+	key := fmt.Sprintf("%s/%s", clusterName, serviceName)
+
+	c.lck.Lock()
+	result, ok := c.ecsServices[key]
+	if !ok {
+		result = &ecsServiceResult{
+			done: make(chan struct{}),
+		}
+		c.ecsServices[key] = result
+		c.enqueueEcsService(ctx, clusterName, serviceName)
+	}
+	c.lck.Unlock()
+
+	select {
+	case <-result.done:
+		return result.service, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// DescribeEcsTaskDefinition returns the cached description of the task definition revision, it is described with the
+// context of the first read asking for it
+func (c *AwsClients) DescribeEcsTaskDefinition(ctx context.Context, taskDefinitionArn string) (*ecsTypes.TaskDefinition, error) {
+	c.lck.Lock()
+	result, ok := c.ecsTaskDefinitions[taskDefinitionArn]
+	if !ok {
+		result = &ecsTaskDefinitionResult{
+			done: make(chan struct{}),
+		}
+		c.ecsTaskDefinitions[taskDefinitionArn] = result
+	}
+	c.lck.Unlock()
+
+	if !ok {
+		result.taskDefinition, result.err = c.describeEcsTaskDefinition(ctx, taskDefinitionArn)
+
+		if result.err != nil {
+			c.lck.Lock()
+			delete(c.ecsTaskDefinitions, taskDefinitionArn)
+			c.lck.Unlock()
+		}
+
+		close(result.done)
+	}
+
+	select {
+	case <-result.done:
+		return result.taskDefinition, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *AwsClients) describeEcsTaskDefinition(ctx context.Context, taskDefinitionArn string) (*ecsTypes.TaskDefinition, error) {
+	var output *ecs.DescribeTaskDefinitionOutput
+
+	ecsSvc, err := c.Ecs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.retryThrottled(func() error {
+		output, err = ecsSvc.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(taskDefinitionArn),
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not describe ecs task definition %s: %w", taskDefinitionArn, err)
+	}
+
+	if output.TaskDefinition == nil {
+		return nil, fmt.Errorf("there was no ecs task definition %s found", taskDefinitionArn)
+	}
+
+	return output.TaskDefinition, nil
+}
+
+// DescribeElbTargetGroupsByName returns the target groups with the given names
+func (c *AwsClients) DescribeElbTargetGroupsByName(ctx context.Context, names []string) ([]ElbTargetGroup, error) {
+	var output *elasticloadbalancingv2.DescribeTargetGroupsOutput
+
+	elbSvc, err := c.Elbv2(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.retryThrottled(func() error {
+		output, err = elbSvc.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{
+			Names: names,
+		})
+
+		return err
+	})
+	if err != nil {
+		var notFound *elbTypes.TargetGroupNotFoundException
+		if errors.As(err, &notFound) {
+			return []ElbTargetGroup{}, nil
+		}
+
+		return nil, fmt.Errorf("can not describe target groups %v: %w", names, err)
+	}
+
+	return newElbTargetGroups(output.TargetGroups)
+}
+
+// DescribeElbTargetGroupsByTags returns the target groups carrying all the given tags. The target groups of the account
+// are listed together with their tags once and shared by all reads, a failed listing isn't cached.
+func (c *AwsClients) DescribeElbTargetGroupsByTags(ctx context.Context, tags map[string]string) ([]ElbTargetGroup, error) {
+	c.lck.Lock()
+	result := c.elbTargetGroups
+	ok := result != nil
+	if !ok {
+		result = &elbTargetGroupsResult{
+			done: make(chan struct{}),
+		}
+		c.elbTargetGroups = result
+	}
+	c.lck.Unlock()
+
+	if !ok {
+		result.targetGroups, result.err = c.describeTaggedElbTargetGroups(ctx)
+
+		if result.err != nil {
+			c.lck.Lock()
+			c.elbTargetGroups = nil
+			c.lck.Unlock()
+		}
+
+		close(result.done)
+	}
+
+	select {
+	case <-result.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	matching := make([]elbTypes.TargetGroup, 0)
+
+	for _, targetGroup := range result.targetGroups {
+		if hasTags(targetGroup.tags, tags) {
+			matching = append(matching, targetGroup.targetGroup)
+		}
+	}
+
+	return newElbTargetGroups(matching)
+}
+
+func (c *AwsClients) describeTaggedElbTargetGroups(ctx context.Context) ([]taggedElbTargetGroup, error) {
+	elbSvc, err := c.Elbv2(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targetGroups := make([]taggedElbTargetGroup, 0)
+	paginator := elasticloadbalancingv2.NewDescribeTargetGroupsPaginator(elbSvc, &elasticloadbalancingv2.DescribeTargetGroupsInput{})
+
+	for paginator.HasMorePages() {
+		var output *elasticloadbalancingv2.DescribeTargetGroupsOutput
+
+		err = c.retryThrottled(func() error {
+			output, err = paginator.NextPage(ctx)
+
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("can not describe target groups: %w", err)
+		}
+
+		for _, targetGroup := range output.TargetGroups {
+			targetGroups = append(targetGroups, taggedElbTargetGroup{
+				targetGroup: targetGroup,
+				tags:        map[string]string{},
+			})
+		}
+	}
+
+	for start := 0; start < len(targetGroups); start += elbDescribeTagsBatchSize {
+		end := min(start+elbDescribeTagsBatchSize, len(targetGroups))
+
+		if err = c.describeElbTargetGroupTags(ctx, elbSvc, targetGroups[start:end]); err != nil {
+			return nil, err
+		}
+	}
+
+	return targetGroups, nil
+}
+
+func (c *AwsClients) describeElbTargetGroupTags(ctx context.Context, elbSvc *elasticloadbalancingv2.Client, targetGroups []taggedElbTargetGroup) error {
+	var err error
+	var output *elasticloadbalancingv2.DescribeTagsOutput
+
+	arns := make([]string, len(targetGroups))
+	byArn := make(map[string]map[string]string, len(targetGroups))
+
+	for i, targetGroup := range targetGroups {
+		arns[i] = aws.ToString(targetGroup.targetGroup.TargetGroupArn)
+		byArn[arns[i]] = targetGroup.tags
+	}
+
+	err = c.retryThrottled(func() error {
+		output, err = elbSvc.DescribeTags(ctx, &elasticloadbalancingv2.DescribeTagsInput{
+			ResourceArns: arns,
+		})
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("can not describe the tags of the target groups %v: %w", arns, err)
+	}
+
+	for _, description := range output.TagDescriptions {
+		tags, ok := byArn[aws.ToString(description.ResourceArn)]
+		if !ok {
+			continue
+		}
+
+		for _, tag := range description.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+
+	return nil
+}
+
+func hasTags(tags map[string]string, expected map[string]string) bool {
+	for key, value := range expected {
+		if actual, ok := tags[key]; !ok || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// enqueueEcsService has to be called while holding the lock
+func (c *AwsClients) enqueueEcsService(ctx context.Context, clusterName, serviceName string) {
+	batch, ok := c.pendingServices[clusterName]
+	if !ok {
+		batch = &ecsServiceBatch{
+			ctx: context.WithoutCancel(ctx),
+		}
+	}
+
+	batch.serviceNames = append(batch.serviceNames, serviceName)
+
+	if len(batch.serviceNames) >= ecsDescribeServicesBatchSize {
+		delete(c.pendingServices, clusterName)
+		go c.describeEcsServices(batch.ctx, clusterName, batch.serviceNames)
+
+		return
+	}
+
+	c.pendingServices[clusterName] = batch
+
+	if !ok {
+		time.AfterFunc(ecsDescribeServicesBatchWindow, func() {
+			c.flushEcsServices(clusterName)
+		})
+	}
+}
+
+func (c *AwsClients) flushEcsServices(clusterName string) {
+	c.lck.Lock()
+	batch, ok := c.pendingServices[clusterName]
+	delete(c.pendingServices, clusterName)
+	c.lck.Unlock()
+
+	if !ok {
+		return
+	}
+
+	c.describeEcsServices(batch.ctx, clusterName, batch.serviceNames)
+}
+
+// describeEcsServices completes the results of the services, failed results are evicted from the cache
+func (c *AwsClients) describeEcsServices(ctx context.Context, clusterName string, serviceNames []string) {
+	ctx, cancel := context.WithTimeout(ctx, ecsDescribeServicesTimeout)
+	defer cancel()
+
+	services := make(map[string]*ecsTypes.Service, len(serviceNames))
+
+	err := c.describeEcsServicesWithRetry(ctx, clusterName, serviceNames, services)
+
+	c.lck.Lock()
+	defer c.lck.Unlock()
+
+	for _, serviceName := range serviceNames {
+		key := fmt.Sprintf("%s/%s", clusterName, serviceName)
+		result := c.ecsServices[key]
+
+		switch service, ok := services[serviceName]; {
+		case err != nil:
+			result.err = fmt.Errorf("can not describe ecs service %s/%s: %w", clusterName, serviceName, err)
+		case !ok:
+			result.err = fmt.Errorf("there was no ecs service %s/%s found", clusterName, serviceName)
+		default:
+			result.service = service
+		}
+
+		if result.err != nil {
+			delete(c.ecsServices, key)
+		}
+
+		close(result.done)
+	}
+}
+
+func (c *AwsClients) describeEcsServicesWithRetry(ctx context.Context, clusterName string, serviceNames []string, services map[string]*ecsTypes.Service) error {
+	var output *ecs.DescribeServicesOutput
+
+	ecsSvc, err := c.Ecs(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.retryThrottled(func() error {
+		output, err = ecsSvc.DescribeServices(ctx, &ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterName),
+			Services: serviceNames,
+		})
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range output.Services {
+		services[aws.ToString(output.Services[i].ServiceName)] = &output.Services[i]
+	}
+
+	return nil
+}
+
+// retryThrottled retries the operation with backoff as long as it fails with a throttling error
+func (c *AwsClients) retryThrottled(operation func() error) error {
+	throttleErrors := retry.ThrottleErrorCode{
+		Codes: retry.DefaultThrottleErrorCodes,
+	}
+
+	return backoff.Retry(func() error {
+		err := operation()
+
+		if err != nil && throttleErrors.IsErrorThrottle(err) != aws.TrueTernary {
+			return backoff.Permanent(err)
+		}
+
+		return err
+	}, c.backoffFactory())
+}
@@ -1,46 +1,278 @@
 package builder
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	DashboadWidth = 24
+	PanelWidth    = 12
+	PanelHeight   = 8
+)
 
 const (
-	DashboadWidth          = 24
-	PanelWidth             = 12
-	PanelHeight            = 8
-	orchestratorEcs        = "ecs"
-	orchestratorKubernetes = "kubernetes"
+	DashboardSectionAll            = "all"
+	DashboardSectionDdb            = "ddb"
+	DashboardSectionElb            = "elb"
+	DashboardSectionErrors         = "errors"
+	DashboardSectionHttpRoutes     = "http_routes"
+	DashboardSectionKinesis        = "kinesis"
+	DashboardSectionLogs           = "logs"
+	DashboardSectionOverview       = "overview"
+	DashboardSectionResources      = "resources"
+	DashboardSectionSns            = "sns"
+	DashboardSectionSqs            = "sqs"
+	DashboardSectionStreamConsumer = "stream_consumer"
+	DashboardSectionStreamProducer = "stream_producer"
+	DashboardSectionTraefik        = "traefik"
 )
 
 type Dashboard struct {
-	Title  string  `json:"title"`
-	Panels []Panel `json:"panels"`
+	Uid          string                `json:"uid,omitempty"`
+	Title        string                `json:"title"`
+	Tags         []string              `json:"tags"`
+	Refresh      string                `json:"refresh"`
+	Time         DashboardTime         `json:"time"`
+	Timezone     string                `json:"timezone"`
+	GraphTooltip int                   `json:"graphTooltip"`
+	Links        []DashboardLink       `json:"links"`
+	Panels       []Panel               `json:"panels"`
+	Templating   *DashboardTemplating  `json:"templating,omitempty"`
+	Annotations  *DashboardAnnotations `json:"annotations,omitempty"`
 }
 
 type DashboardBuilder struct {
-	resourceNames  *ResourceNames
-	panelFactories []PanelFactory
-	orchestrator   string
+	resourceNames     *ResourceNames
+	panelFactories    []sectionPanel
+	section           string
+	orchestrator      Orchestrator
+	ingress           Ingress
+	collapsedSections map[string]bool
+	templating        *dashboardTemplating
+	annotations       DashboardAnnotationSettings
+	settings          DashboardSettings
+	filter            *DashboardFilter
+	httpAggregate     *HttpServerAggregate
+	percentiles       []Percentile
+	theme             Theme
+	thresholds        Thresholds
+	layout            *PanelLayout
+	err               error
+}
+
+// sectionPanel is a panel factory together with the section whose panel size applies to the panel
+type sectionPanel struct {
+	section string
+	factory PanelFactory
+}
+
+type DashboardBuilderOpt func(d *DashboardBuilder)
+
+// WithIngress selects the registered ingress with the given name, NewDashboardBuilder fails for unknown names
+func WithIngress(ingressName string) DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		ingress, ok := GetIngress(ingressName)
+		if !ok {
+			d.err = fmt.Errorf("'%s' is not a valid ingress, choose between %v", ingressName, AvailableIngresses())
+
+			return
+		}
+
+		d.ingress = ingress
+	}
+}
+
+// WithCollapsedSections collapses the rows of the given sections, DashboardSectionAll collapses every row of the dashboard
+func WithCollapsedSections(sections ...string) DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		for _, section := range sections {
+			d.collapsedSections[section] = true
+		}
+	}
+}
+
+// WithTemplating adds template variables for the datasources, containers, routes and the interval to the dashboard.
+// Instead of a section per container and route, the panels are repeated by grafana for the selected values.
+func WithTemplating() DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		d.templating = &dashboardTemplating{}
+	}
+}
+
+// WithAnnotations configures the annotations of the dashboard, by default the deployments are marked
+func WithAnnotations(settings DashboardAnnotationSettings) DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		d.annotations = settings
+	}
+}
+
+// WithSettings sets the dashboard wide settings like the uid, tags and the time range
+func WithSettings(settings DashboardSettings) DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		d.settings = settings
+	}
+}
+
+// WithFilter skips the sections, routes and resources which are not included by the filter
+func WithFilter(filter *DashboardFilter) DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		d.filter = filter
+	}
+}
+
+// WithHttpServerAggregate renders a section with the totals and top routes per http server instead of a section per route
+func WithHttpServerAggregate(aggregate *HttpServerAggregate) DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		d.httpAggregate = aggregate
+	}
+}
+
+// WithPercentiles shows the given percentiles next to the average in the response time panels of the http servers,
+// load balancers and ingresses
+func WithPercentiles(percentiles []Percentile) DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		d.percentiles = percentiles
+	}
+}
+
+// WithTheme sets the colors of the series of the panels
+func WithTheme(theme Theme) DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		d.theme = theme
+	}
+}
+
+// WithThresholds sets the thresholds of the panel kinds, which are drawn as lines into the panels
+func WithThresholds(thresholds Thresholds) DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		d.thresholds = thresholds
+	}
+}
+
+// WithLayout sets the default size of the panels and the sizes of the panels of single sections
+func WithLayout(layout *PanelLayout) DashboardBuilderOpt {
+	return func(d *DashboardBuilder) {
+		d.layout = layout
+	}
+}
+
+// NewDashboardBuilder creates a builder for the registered orchestrator with the given name
+func NewDashboardBuilder(resourceNames *ResourceNames, orchestratorName string, opts ...DashboardBuilderOpt) (*DashboardBuilder, error) {
+	orchestrator, ok := GetOrchestrator(orchestratorName)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a valid orchestrator, choose between %v", orchestratorName, AvailableOrchestrators())
+	}
+
+	d := &DashboardBuilder{
+		resourceNames:     resourceNames,
+		panelFactories:    make([]sectionPanel, 0),
+		orchestrator:      orchestrator,
+		ingress:           traefikIngress{},
+		collapsedSections: make(map[string]bool),
+		annotations: DashboardAnnotationSettings{
+			Deployments: true,
+		},
+		settings: newDefaultDashboardSettings(),
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return d, nil
 }
 
-func NewDashboardBuilder(resourceNames *ResourceNames, orchestrator string) *DashboardBuilder {
-	return &DashboardBuilder{
-		resourceNames:  resourceNames,
-		panelFactories: make([]PanelFactory, 0),
-		orchestrator:   orchestrator,
+// AddOverview adds a row of headline stats: the errors, the 5xx ratio and p99 latency of the http servers, the running
+// replicas, the highest kinsumer lag and the largest sqs backlog. Stats without resources to show are left out.
+func (d *DashboardBuilder) AddOverview(metadata *MetadataApplication) {
+	if !d.filter.IncludesSection(DashboardSectionOverview) {
+		return
+	}
+
+	panels := []PanelFactory{NewPanelOverviewErrors}
+
+	if len(metadata.HttpServers) > 0 {
+		panels = append(panels, NewPanelOverviewHttp5xxRatio, NewPanelOverviewHttpP99)
+	}
+
+	panels = append(panels, d.orchestrator.ReplicaOverviewPanel(d.resourceNames))
+
+	streamNames := make([]string, 0)
+	for _, kinsumer := range metadata.Cloud.Aws.Kinesis.Kinsumers {
+		if d.filter.IncludesStream(kinsumer.StreamNameFull) {
+			streamNames = append(streamNames, kinsumer.StreamNameFull)
+		}
+	}
+
+	if len(streamNames) > 0 {
+		sort.Strings(streamNames)
+		panels = append(panels, NewPanelOverviewKinsumerLag(streamNames))
+	}
+
+	queueNames := make([]string, 0)
+	for _, queue := range metadata.Cloud.Aws.Sqs.Queues {
+		if d.filter.IncludesQueue(queue.QueueNameFull) {
+			queueNames = append(queueNames, queue.QueueNameFull)
+		}
+	}
+
+	if len(queueNames) > 0 {
+		sort.Strings(queueNames)
+		panels = append(panels, NewPanelOverviewSqsBacklog(queueNames))
+	}
+
+	d.addSectionRow(DashboardSectionOverview, "Overview")
+
+	width := DashboadWidth / len(panels)
+	for _, panel := range panels {
+		d.AddPanel(overviewPanel(panel, width))
 	}
 }
 
 func (d *DashboardBuilder) AddServiceAndTask() {
-	d.AddPanel(NewPanelRow("Service Resource Usage"))
-	d.AddPanel(NewPanelServiceUtilization)
-	d.AddPanel(NewPanelTaskDeployment)
-	for i := range d.resourceNames.Containers {
-		d.AddPanel(NewPanelContainerCpuFactory(i))
-		d.AddPanel(NewPanelContainerMemoryFactory(i))
+	if !d.filter.IncludesSection(DashboardSectionResources) {
+		return
+	}
+
+	d.addSectionRow(DashboardSectionResources, "Service Resource Usage")
+	for _, panel := range d.orchestrator.ResourceUsagePanels(d.panelResourceNames()) {
+		d.AddPanel(panel)
+	}
+}
+
+// AddErrorsAndWarnings adds the error and warning counts as well as the logs of the application
+func (d *DashboardBuilder) AddErrorsAndWarnings() {
+	withErrors := d.filter.IncludesSection(DashboardSectionErrors)
+	withLogs := d.filter.IncludesSection(DashboardSectionLogs)
+
+	if !withErrors && !withLogs {
+		return
+	}
+
+	d.addSectionRow(DashboardSectionErrors, "Errors & Warnings")
+
+	if withErrors {
+		d.AddPanel(NewPanelError)
+		d.AddPanel(NewPanelWarn)
+	}
+
+	if withLogs {
+		d.section = DashboardSectionLogs
+		d.AddPanel(NewPanelLogs)
 	}
 }
 
 func (d *DashboardBuilder) AddElbTargetGroup(targetGroupIndex int) {
-	d.AddPanel(NewPanelRow("Load Balancer"))
+	if !d.filter.IncludesSection(DashboardSectionElb) {
+		return
+	}
+
+	d.addSectionRow(DashboardSectionElb, "Load Balancer")
 	d.AddPanel(NewPanelElbRequestCount(targetGroupIndex))
 	d.AddPanel(NewPanelElbResponseTime(targetGroupIndex))
 	d.AddPanel(NewPanelElbHttpStatus(targetGroupIndex))
@@ -48,32 +280,115 @@ func (d *DashboardBuilder) AddElbTargetGroup(targetGroupIndex int) {
 	d.AddPanel(NewPanelElbRequestCountPerTarget(targetGroupIndex))
 }
 
+// AddElbTargetGroups adds the target groups of the ecs service. On kubernetes, the target groups are part of the ingress.
+func (d *DashboardBuilder) AddElbTargetGroups() {
+	if d.orchestrator.Name() == orchestratorKubernetes {
+		return
+	}
+
+	for i := range d.resourceNames.TargetGroups {
+		d.AddElbTargetGroup(i)
+	}
+}
+
+// AddTraefikService adds the panels of the selected ingress, which is traefik unless configured otherwise
 func (d *DashboardBuilder) AddTraefikService() {
-	if d.orchestrator != orchestratorKubernetes {
+	d.AddIngress()
+}
+
+func (d *DashboardBuilder) AddIngress() {
+	if d.orchestrator.Name() != orchestratorKubernetes || !d.filter.IncludesSection(DashboardSectionTraefik) {
 		return
 	}
 
-	d.AddPanel(NewPanelRow("Traefik"))
-	d.AddPanel(NewPanelTraefikRequestCount)
-	d.AddPanel(NewPanelTraefikResponseTime)
-	d.AddPanel(NewPanelTraefikHttpStatus)
-	d.AddPanel(NewPanelKubernetesHealthyPods)
-	d.AddPanel(NewPanelTraefikRequestCountPerTarget)
+	d.section = DashboardSectionTraefik
+	for _, panel := range d.ingress.Panels(d.resourceNames) {
+		d.AddPanel(panel)
+	}
+}
+
+// AddHttpServer adds a section per route of the server or, in aggregate mode, a single section for all of its routes
+func (d *DashboardBuilder) AddHttpServer(server MetadataHttpServer) {
+	if d.httpAggregate == nil {
+		for _, handler := range server.Handlers {
+			d.AddHttpServerHandler(server.Name, handler)
+		}
+
+		return
+	}
+
+	if !d.filter.IncludesSection(DashboardSectionHttpRoutes) {
+		return
+	}
+
+	rowTitle := fmt.Sprintf("HttpServer %s: All Routes", server.Name)
+
+	d.addSectionRow(DashboardSectionHttpRoutes, rowTitle)
+	d.AddPanel(NewPanelHttpServerTotalRequestCount(server))
+	d.AddPanel(NewPanelHttpServerTotalResponseTime(server))
+	d.AddPanel(NewPanelHttpServerTotalHttpStatus(server))
+	d.AddPanel(NewPanelHttpServerBusiestRoutes(server, d.httpAggregate.topRoutes))
+	d.AddPanel(NewPanelHttpServerSlowestRoutes(server, d.httpAggregate.topRoutes))
+
+	for _, handler := range server.Handlers {
+		if d.httpAggregate.includesRouteSection(handler) {
+			d.AddHttpServerHandler(server.Name, handler)
+		}
+	}
 }
 
 func (d *DashboardBuilder) AddHttpServerHandler(serverName string, handler MetadataHttpServerHandler) {
+	if !d.filter.IncludesSection(DashboardSectionHttpRoutes) || !d.filter.IncludesRoute(handler) {
+		return
+	}
+
+	if d.templating != nil {
+		d.addHttpServerRoute(handler)
+
+		return
+	}
+
 	rowTitle := fmt.Sprintf("HttpServer %s: %s %s", serverName, handler.Method, handler.Path)
 
-	d.AddPanel(NewPanelRow(rowTitle))
+	d.addSectionRow(DashboardSectionHttpRoutes, rowTitle)
 	d.AddPanel(NewPanelHttpServerRequestCount(serverName, handler))
 	d.AddPanel(NewPanelHttpServerResponseTime(serverName, handler))
 	d.AddPanel(NewPanelHttpServerHttpStatus(serverName, handler))
 }
 
+// addHttpServerRoute adds a single section for all routes which is repeated for every selected route. As the route
+// variable only selects the path, the requests of all servers and methods are shown.
+func (d *DashboardBuilder) addHttpServerRoute(handler MetadataHttpServerHandler) {
+	first := len(d.templating.routes) == 0
+	d.templating.addRoute(handler)
+
+	if !first {
+		return
+	}
+
+	serverName := "*"
+	route := MetadataHttpServerHandler{
+		Method: "*",
+		Path:   TemplateVariableRef(TemplateVariableRoute),
+	}
+
+	rowTitle := fmt.Sprintf("HttpServer: %s", route.Path)
+
+	d.section = DashboardSectionHttpRoutes
+	d.AddPanel(repeatPanel(d.sectionRow(DashboardSectionHttpRoutes, rowTitle), TemplateVariableRoute))
+	d.AddPanel(NewPanelHttpServerRequestCount(serverName, route))
+	d.AddPanel(NewPanelHttpServerResponseTime(serverName, route))
+	d.AddPanel(NewPanelHttpServerHttpStatus(serverName, route))
+}
+
 func (d *DashboardBuilder) AddDynamoDbTable(table MetadataCloudAwsDynamodbTable) {
+	if !d.filter.IncludesSection(DashboardSectionDdb) || !d.filter.IncludesTable(table.TableName) {
+		return
+	}
+
 	rowTitle := fmt.Sprintf("Dynamodb: %s", table.TableName)
 
-	d.AddPanel(NewPanelRow(rowTitle))
+	d.addSectionRow(DashboardSectionDdb, rowTitle)
 	d.AddPanel(NewPanelDdbReadUsage(table))
 	d.AddPanel(NewPanelDdbReadThrottles(table))
 	d.AddPanel(NewPanelDdbWriteUsage(table))
@@ -81,9 +396,13 @@ func (d *DashboardBuilder) AddDynamoDbTable(table MetadataCloudAwsDynamodbTable)
 }
 
 func (d *DashboardBuilder) AddCloudAwsKinesisKinsumer(stream MetadataCloudAwsKinesisKinsumer) {
+	if !d.filter.IncludesSection(DashboardSectionKinesis) || !d.filter.IncludesStream(stream.StreamNameFull) {
+		return
+	}
+
 	rowTitle := fmt.Sprintf("Kinsumer on Stream: %s (%d Shards)", stream.StreamNameFull, stream.OpenShardCount)
 
-	d.AddPanel(NewPanelRow(rowTitle))
+	d.addSectionRow(DashboardSectionKinesis, rowTitle)
 	d.AddPanel(NewPanelKinesisKinsumerMillisecondsBehind(stream))
 	d.AddPanel(NewPanelKinesisKinsumerMessageCounts(stream))
 	d.AddPanel(NewPanelKinesisKinsumerReadOperations(stream))
@@ -91,17 +410,25 @@ func (d *DashboardBuilder) AddCloudAwsKinesisKinsumer(stream MetadataCloudAwsKin
 }
 
 func (d *DashboardBuilder) AddCloudAwsKinesisRecordWriter(stream MetadataCloudAwsKinesisRecordWriter) {
+	if !d.filter.IncludesSection(DashboardSectionKinesis) || !d.filter.IncludesStream(stream.StreamName) {
+		return
+	}
+
 	rowTitle := fmt.Sprintf("Kinesis RecordWriter on Stream: %s (%d Shards)", stream.StreamName, stream.OpenShardCount)
 
-	d.AddPanel(NewPanelRow(rowTitle))
+	d.addSectionRow(DashboardSectionKinesis, rowTitle)
 	d.AddPanel(NewPanelKinesisRecordWriterPutRecordsCount(stream))
 	d.AddPanel(NewPanelKinesisRecordWriterPutRecordsBatchSize(stream))
 }
 
 func (d *DashboardBuilder) AddCloudAwsKinesisStream(stream KinesisStreamAware) {
+	if !d.filter.IncludesSection(DashboardSectionKinesis) || !d.filter.IncludesStream(stream.GetStreamNameFull()) {
+		return
+	}
+
 	rowTitle := fmt.Sprintf("Kinesis Stream: %s (%d Shards)", stream.GetStreamNameFull(), stream.GetOpenShardCount())
 
-	d.AddPanel(NewPanelRow(rowTitle))
+	d.addSectionRow(DashboardSectionKinesis, rowTitle)
 
 	d.AddPanel(NewPanelKinesisStreamSuccessRate(stream))
 	d.AddPanel(NewPanelKinesisStreamGetRecordsBytes(stream))
@@ -111,18 +438,69 @@ func (d *DashboardBuilder) AddCloudAwsKinesisStream(stream KinesisStreamAware) {
 }
 
 func (d *DashboardBuilder) AddCloudAwsSqsQueue(queue MetadataCloudAwsSqsQueue) {
+	if !d.filter.IncludesSection(DashboardSectionSqs) || !d.filter.IncludesQueue(queue.QueueNameFull) {
+		return
+	}
+
 	rowTitle := fmt.Sprintf("SQS: %s", queue.QueueNameFull)
 
-	d.AddPanel(NewPanelRow(rowTitle))
+	d.addSectionRow(DashboardSectionSqs, rowTitle)
 	d.AddPanel(NewPanelSqsMessagesVisible(queue))
 	d.AddPanel(NewPanelSqsTraffic(queue))
 	d.AddPanel(NewPanelSqsMessageSize(queue))
 }
 
+func (d *DashboardBuilder) AddCloudAwsSnsTopic(topic MetadataCloudAwsSnsTopic) {
+	if !d.filter.IncludesSection(DashboardSectionSns) || !d.filter.IncludesTopic(topic.TopicName) {
+		return
+	}
+
+	rowTitle := fmt.Sprintf("SNS: %s", topic.TopicName)
+
+	d.addSectionRow(DashboardSectionSns, rowTitle)
+	d.AddPanel(NewPanelSnsMessagesPublished(topic))
+	d.AddPanel(NewPanelSnsNotifications(topic))
+	d.AddPanel(NewPanelSnsPublishSize(topic))
+	d.AddPanel(NewPanelSnsFilteredNotifications(topic))
+}
+
+// AddExtraSection adds a section supplied by the user, the placeholders of the app id are replaced in its title and panels
+func (d *DashboardBuilder) AddExtraSection(section ExtraSection, appId AppId) error {
+	panels := make([]PanelFactory, len(section.Panels))
+
+	for i, extra := range section.Panels {
+		panel, err := NewPanelExtra(extra, appId)
+		if err != nil {
+			return fmt.Errorf("can not create panel %d of the section %q: %w", i, section.Title, err)
+		}
+
+		panels[i] = panel
+	}
+
+	rowTitle := Augment(section.Title, appId)
+
+	d.section = ""
+	if section.Collapsed {
+		d.AddPanel(NewPanelCollapsedRow(rowTitle))
+	} else {
+		d.AddPanel(NewPanelRow(rowTitle))
+	}
+
+	for _, panel := range panels {
+		d.AddPanel(panel)
+	}
+
+	return nil
+}
+
 func (d *DashboardBuilder) AddStreamConsumer(consumer MetadataStreamConsumer) {
+	if !d.filter.IncludesSection(DashboardSectionStreamConsumer) {
+		return
+	}
+
 	rowTitle := fmt.Sprintf("Stream Consumer: %s", consumer.Name)
 
-	d.AddPanel(NewPanelRow(rowTitle))
+	d.addSectionRow(DashboardSectionStreamConsumer, rowTitle)
 	d.AddPanel(NewPanelStreamConsumerProcessedCount(consumer))
 	d.AddPanel(NewPanelStreamConsumerProcessDuration(consumer))
 
@@ -132,53 +510,170 @@ func (d *DashboardBuilder) AddStreamConsumer(consumer MetadataStreamConsumer) {
 }
 
 func (d *DashboardBuilder) AddStreamProducerDaemon(producer MetadataStreamProducer) {
+	if !d.filter.IncludesSection(DashboardSectionStreamProducer) {
+		return
+	}
+
 	rowTitle := fmt.Sprintf("Stream Producer Daemon: %s", producer.Name)
 
-	d.AddPanel(NewPanelRow(rowTitle))
+	d.addSectionRow(DashboardSectionStreamProducer, rowTitle)
 	d.AddPanel(NewPanelStreamProducerDaemonSizes(producer))
 	d.AddPanel(NewPanelStreamProducerMessageCount(producer))
 }
 
+func (d *DashboardBuilder) addSectionRow(section string, title string) {
+	d.section = section
+	d.AddPanel(d.sectionRow(section, title))
+}
+
+func (d *DashboardBuilder) sectionRow(section string, title string) PanelFactory {
+	if d.collapsedSections[section] {
+		return NewPanelCollapsedRow(title)
+	}
+
+	return NewPanelRow(title)
+}
+
+// panelResourceNames returns the resource names the panels are built for, which reference the template variables
+// instead of the actual values if the dashboard has template variables
+func (d *DashboardBuilder) panelResourceNames() *ResourceNames {
+	if d.templating == nil {
+		return d.resourceNames
+	}
+
+	resourceNames := *d.resourceNames
+	resourceNames.Containers = []string{TemplateVariableRef(TemplateVariableContainer)}
+
+	return &resourceNames
+}
+
 func (d *DashboardBuilder) AddPanel(panel PanelFactory) {
-	d.panelFactories = append(d.panelFactories, panel)
+	d.panelFactories = append(d.panelFactories, sectionPanel{
+		section: d.section,
+		factory: panel,
+	})
 }
 
 func (d *DashboardBuilder) Build(title string) Dashboard {
-	var x, y int
-	panels := make([]Panel, len(d.panelFactories))
+	layout := newDashboardLayout()
+	panels := make([]Panel, 0, len(d.panelFactories))
 
-	for i, factory := range d.panelFactories {
-		panel := d.buildPanel(factory, x, y)
-		panels[i] = panel
+	// the panels following a collapsed row are part of the row and laid out below it, as if it was expanded
+	var collapsedRow int
+	var rowLayout *dashboardLayout
+
+	width, height := d.layout.defaultSize()
+
+	for _, entry := range d.panelFactories {
+		current := layout
+		if rowLayout != nil {
+			current = rowLayout
+		}
+
+		panel := d.buildPanel(entry.factory, current.cursor(width, height))
+		panel = d.layout.resize(entry.section, panel)
 
-		x += panel.GridPos.W
+		if panel.Type != "row" && rowLayout != nil {
+			panels[collapsedRow].Panels = append(panels[collapsedRow].Panels, rowLayout.place(panel))
 
-		if x >= DashboadWidth {
-			x = 0
-			y += panel.GridPos.W
+			continue
+		}
+
+		if panel.Type == "row" && d.collapsedSections[DashboardSectionAll] {
+			panel.Collapsed = true
+		}
+
+		panels = append(panels, layout.place(panel))
+		rowLayout = nil
+
+		if panel.Collapsed {
+			collapsedRow = len(panels) - 1
+			rowLayout = newDashboardLayoutBelow(panels[collapsedRow].GridPos)
+			panels[collapsedRow].Panels = []Panel{}
 		}
 	}
 
+	newPanelIds().assign(panels)
+
 	if title == "" {
-		switch d.orchestrator {
-		case orchestratorEcs:
-			title = d.resourceNames.EcsTaskDefinition
-		case orchestratorKubernetes:
-			title = fmt.Sprintf("%s-%s-%s", d.resourceNames.Environment, d.resourceNames.KubernetesNamespace, d.resourceNames.KubernetesPod)
+		title = d.orchestrator.DefaultTitle(d.resourceNames)
+	}
+
+	// grafana expects lists instead of nulls
+	if d.settings.Tags == nil {
+		d.settings.Tags = []string{}
+	}
+
+	if d.settings.Links == nil {
+		d.settings.Links = []DashboardLink{}
+	}
+
+	dashboard := Dashboard{
+		Uid:          d.settings.Uid,
+		Title:        title,
+		Tags:         d.settings.Tags,
+		Refresh:      d.settings.Refresh,
+		Time:         d.settings.Time,
+		Timezone:     d.settings.Timezone,
+		GraphTooltip: d.settings.GraphTooltip,
+		Links:        d.settings.Links,
+		Panels:       panels,
+	}
+
+	if d.templating != nil {
+		dashboard.Templating = d.templating.build(d.resourceNames)
+	}
+
+	dashboard.Annotations = d.buildAnnotations()
+
+	return dashboard
+}
+
+func (d *DashboardBuilder) buildAnnotations() *DashboardAnnotations {
+	annotations := make([]DashboardAnnotation, 0)
+
+	if d.annotations.Deployments {
+		if annotation, ok := d.orchestrator.DeploymentAnnotation(d.resourceNames); ok {
+			annotations = append(annotations, annotation)
 		}
 	}
 
-	return Dashboard{
-		Title:  title,
-		Panels: panels,
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	for i := range annotations {
+		if d.annotations.IconColor != "" {
+			annotations[i].IconColor = d.annotations.IconColor
+		}
+
+		if d.templating != nil && annotations[i].Datasource == datasourcePrometheus {
+			annotations[i].Datasource = TemplateVariableDatasourceRef(TemplateVariableDatasourcePrometheus)
+		}
+	}
+
+	return &DashboardAnnotations{
+		List: annotations,
 	}
 }
 
-func (d *DashboardBuilder) buildPanel(factory PanelFactory, x int, y int) Panel {
-	panelGridPos := NewPanelGridPos(PanelHeight, PanelWidth, x, y)
-	settings := newPanelSettings(d.resourceNames, panelGridPos, d.orchestrator)
+func (d *DashboardBuilder) buildPanel(factory PanelFactory, gridPos PanelGridPos) Panel {
+	settings := newPanelSettings(d.panelResourceNames(), gridPos, d.orchestrator, d.templating != nil, d.percentiles, d.theme, d.thresholds)
 	panel := factory(settings)
 
+	if d.templating != nil {
+		panel = applyTemplateVariableInterval(panel)
+	}
+
+	if d.templating != nil && panel.Datasource != "" {
+		switch panel.Datasource {
+		case d.resourceNames.GrafanaCloudWatchDatasourceName:
+			panel.Datasource = TemplateVariableDatasourceRef(TemplateVariableDatasourceCloudWatch)
+		case datasourcePrometheus:
+			panel.Datasource = TemplateVariableDatasourceRef(TemplateVariableDatasourcePrometheus)
+		}
+	}
+
 	if panel.FieldConfig.Defaults.Custom.AxisPlacement == "" {
 		panel.FieldConfig.Defaults.Custom.AxisPlacement = "right"
 	}
@@ -0,0 +1,83 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+const (
+	GraphTooltipDefault         = 0
+	GraphTooltipSharedCrosshair = 1
+	GraphTooltipSharedTooltip   = 2
+
+	// dashboardUidMaxLength is the maximum length of a uid grafana accepts
+	dashboardUidMaxLength = 40
+)
+
+type DashboardTime struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type DashboardLink struct {
+	AsDropdown  bool     `json:"asDropdown"`
+	Icon        string   `json:"icon"`
+	IncludeVars bool     `json:"includeVars"`
+	KeepTime    bool     `json:"keepTime"`
+	Tags        []string `json:"tags"`
+	TargetBlank bool     `json:"targetBlank"`
+	Title       string   `json:"title"`
+	Tooltip     string   `json:"tooltip"`
+	// Type is either "link" to link to the url or "dashboards" to link to all dashboards with the given tags
+	Type string `json:"type"`
+	Url  string `json:"url"`
+}
+
+// DashboardSettings are the dashboard wide settings which are emitted next to the panels
+type DashboardSettings struct {
+	Uid          string
+	Tags         []string
+	Refresh      string
+	Time         DashboardTime
+	Timezone     string
+	GraphTooltip int
+	Links        []DashboardLink
+}
+
+func newDefaultDashboardSettings() DashboardSettings {
+	return DashboardSettings{
+		Tags:    []string{},
+		Refresh: "",
+		Time: DashboardTime{
+			From: "now-3h",
+			To:   "now",
+		},
+		Timezone:     "browser",
+		GraphTooltip: GraphTooltipSharedCrosshair,
+		Links:        []DashboardLink{},
+	}
+}
+
+// NewDashboardSettings returns the default settings for the dashboard of an application, its uid and tags are derived from the app id
+func NewDashboardSettings(appId AppId) DashboardSettings {
+	settings := newDefaultDashboardSettings()
+	settings.Uid = NewDashboardUid(appId)
+	settings.Tags = []string{appId.Project, appId.Environment, appId.Family, appId.Group, appId.Application}
+
+	return settings
+}
+
+// NewDashboardUid derives a stable uid from the app id. Uids exceeding the length grafana accepts are shortened and
+// suffixed with a hash of the app id, so they stay unique.
+func NewDashboardUid(appId AppId) string {
+	uid := strings.Join([]string{appId.Project, appId.Environment, appId.Family, appId.Group, appId.Application}, "-")
+
+	if len(uid) <= dashboardUidMaxLength {
+		return uid
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(uid)))[:8]
+
+	return fmt.Sprintf("%s-%s", uid[:dashboardUidMaxLength-len(hash)-1], hash)
+}
@@ -0,0 +1,70 @@
+package builder_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDashboardUid(t *testing.T) {
+	assert.Equal(t, "prj-env-fam-grp-app", builder.NewDashboardUid(provideAppId()))
+
+	appId := builder.AppId{
+		Project:     "project",
+		Environment: "environment",
+		Family:      "family",
+		Group:       "group",
+		Application: "application",
+	}
+	uid := builder.NewDashboardUid(appId)
+
+	assert.Len(t, uid, 40)
+	assert.Equal(t, "project-environment-family-grou-", uid[:32])
+	assert.Equal(t, uid, builder.NewDashboardUid(appId))
+
+	appId.Application = "application2"
+	assert.NotEqual(t, uid, builder.NewDashboardUid(appId))
+}
+
+func TestDashboardSettings(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		Containers: []string{"app"},
+	}
+
+	settings := builder.NewDashboardSettings(provideAppId())
+	settings.Refresh = "1m"
+	settings.Links = []builder.DashboardLink{
+		{Title: "Runbook", Type: "link", Url: "https://example.com/runbook"},
+	}
+
+	dashboard := newDashboardBuilder(t, resourceNames, "ecs", builder.WithSettings(settings)).Build("title")
+
+	body, err := json.Marshal(dashboard)
+	assert.NoError(t, err)
+
+	result := make(map[string]any)
+	assert.NoError(t, json.Unmarshal(body, &result))
+
+	assert.Equal(t, "prj-env-fam-grp-app", result["uid"])
+	assert.Equal(t, []any{"prj", "env", "fam", "grp", "app"}, result["tags"])
+	assert.Equal(t, "1m", result["refresh"])
+	assert.Equal(t, map[string]any{"from": "now-3h", "to": "now"}, result["time"])
+	assert.Equal(t, "browser", result["timezone"])
+	assert.Equal(t, float64(builder.GraphTooltipSharedCrosshair), result["graphTooltip"])
+	assert.Len(t, result["links"], 1)
+}
+
+func TestDashboardDefaultSettings(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		Containers: []string{"app"},
+	}
+
+	dashboard := newDashboardBuilder(t, resourceNames, "ecs").Build("title")
+
+	assert.Empty(t, dashboard.Uid)
+	assert.Equal(t, []string{}, dashboard.Tags)
+	assert.Equal(t, []builder.DashboardLink{}, dashboard.Links)
+	assert.Equal(t, builder.DashboardTime{From: "now-3h", To: "now"}, dashboard.Time)
+}
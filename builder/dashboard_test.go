@@ -7,6 +7,7 @@ import (
 
 	"github.com/justtrackio/terraform-provider-gosoline/builder"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEcsDashboardWithError(t *testing.T) {
@@ -45,7 +46,7 @@ func TestEcsDashboardWithError(t *testing.T) {
 		TargetGroups:                       targetGroups,
 		Containers:                         containers,
 	}
-	db := builder.NewDashboardBuilder(resourceNames, "ecs")
+	db := newDashboardBuilder(t, resourceNames, "ecs")
 	db.AddPanel(builder.NewPanelServiceUtilization)
 	db.AddPanel(builder.NewPanelTaskDeployment)
 	for i := range containers {
@@ -101,7 +102,7 @@ func TestKubernetesDashboardWithError(t *testing.T) {
 		TraefikServiceName:                 traefikServiceName,
 		Containers:                         containers,
 	}
-	db := builder.NewDashboardBuilder(resourceNames, "kubernetes")
+	db := newDashboardBuilder(t, resourceNames, "kubernetes")
 	db.AddPanel(builder.NewPanelServiceUtilization)
 	db.AddPanel(builder.NewPanelTaskDeployment)
 	for i := range containers {
@@ -118,3 +119,58 @@ func TestKubernetesDashboardWithError(t *testing.T) {
 	assert.Nil(t, err)
 	fmt.Println(string(body))
 }
+
+func TestDashboardSnsTopic(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		GrafanaCloudWatchDatasourceName: "cw",
+	}
+
+	db := newDashboardBuilder(t, resourceNames, "ecs")
+	db.AddCloudAwsSnsTopic(builder.MetadataCloudAwsSnsTopic{
+		TopicArn:  "arn:aws:sns:eu-central-1:123456789012:prj-env-fam-grp-topic",
+		TopicName: "prj-env-fam-grp-topic",
+	})
+	dashboard := db.Build("")
+
+	assert.Len(t, dashboard.Panels, 5)
+	assert.Equal(t, "SNS: prj-env-fam-grp-topic", dashboard.Panels[0].Title)
+
+	var metricNames []string
+	for _, panel := range dashboard.Panels[1:] {
+		assert.Equal(t, "cw", panel.Datasource)
+
+		for _, target := range panel.Targets {
+			cwTarget := target.(builder.PanelTargetCloudWatch)
+			assert.Equal(t, "AWS/SNS", cwTarget.Namespace)
+			assert.Equal(t, map[string]string{"TopicName": "prj-env-fam-grp-topic"}, cwTarget.Dimensions)
+
+			metricNames = append(metricNames, cwTarget.MetricName)
+		}
+	}
+
+	assert.Equal(t, []string{
+		"NumberOfMessagesPublished",
+		"NumberOfNotificationsDelivered",
+		"NumberOfNotificationsFailed",
+		"PublishSize",
+		"PublishSize",
+		"NumberOfNotificationsFilteredOut",
+		"NumberOfNotificationsRedrivenToDlq",
+		"NumberOfNotificationsFailedToRedriveToDlq",
+	}, metricNames)
+}
+
+func newDashboardBuilder(t *testing.T, resourceNames *builder.ResourceNames, orchestratorName string, opts ...builder.DashboardBuilderOpt) *builder.DashboardBuilder {
+	db, err := builder.NewDashboardBuilder(resourceNames, orchestratorName, opts...)
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNewDashboardBuilderUnknownNames(t *testing.T) {
+	_, err := builder.NewDashboardBuilder(&builder.ResourceNames{}, "kubernets")
+	assert.EqualError(t, err, "'kubernets' is not a valid orchestrator, choose between [ecs ecs_fargate kubernetes]")
+
+	_, err = builder.NewDashboardBuilder(&builder.ResourceNames{}, "kubernetes", builder.WithIngress("treafik"))
+	assert.EqualError(t, err, "'treafik' is not a valid ingress, choose between [aws_lb_controller istio nginx traefik]")
+}
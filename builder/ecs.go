@@ -4,59 +4,31 @@ import (
 	"context"
 	"fmt"
 	"regexp"
-	"strings"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 )
 
-type ElbTargetGroup struct {
-	LoadBalancer string
-	TargetGroup  string
-}
-
 type EcsClient struct {
-	ecsSvc      *ecs.Client
-	elbSvc      *elasticloadbalancingv2.Client
+	clients     *AwsClients
 	clusterName string
 	serviceName string
 }
 
-func NewEcsClient(ctx context.Context, clusterName, serviceName string) (*EcsClient, error) {
-	var err error
-	var cfg aws.Config
-
-	if cfg, err = config.LoadDefaultConfig(ctx); err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %w", err)
-	}
-
-	ecsSvc := ecs.NewFromConfig(cfg)
-	elbSvc := elasticloadbalancingv2.NewFromConfig(cfg)
-
+func NewEcsClient(clients *AwsClients, clusterName, serviceName string) *EcsClient {
 	return &EcsClient{
-		ecsSvc:      ecsSvc,
-		elbSvc:      elbSvc,
+		clients:     clients,
 		clusterName: clusterName,
 		serviceName: serviceName,
-	}, nil
+	}
 }
 
 func (c *EcsClient) GetElbTargetGroups(ctx context.Context) ([]ElbTargetGroup, error) {
-	ecsOutput, err := c.ecsSvc.DescribeServices(ctx, &ecs.DescribeServicesInput{
-		Cluster:  aws.String(c.clusterName),
-		Services: []string{c.serviceName},
-	})
+	service, err := c.clients.DescribeEcsService(ctx, c.clusterName, c.serviceName)
 	if err != nil {
-		return nil, fmt.Errorf("can not describe ecs service %s/%s: %w", c.clusterName, c.serviceName, err)
+		return nil, err
 	}
 
-	if len(ecsOutput.Services) != 1 {
-		return nil, fmt.Errorf("there was no ecs service %s/%s found", c.clusterName, c.serviceName)
-	}
-
-	loadbalancers := ecsOutput.Services[0].LoadBalancers
+	loadbalancers := service.LoadBalancers
 	targetGroupArns := make([]string, len(loadbalancers))
 
 	if len(loadbalancers) == 0 {
@@ -67,46 +39,39 @@ func (c *EcsClient) GetElbTargetGroups(ctx context.Context) ([]ElbTargetGroup, e
 		targetGroupArns[i] = *loadbalancer.TargetGroupArn
 	}
 
-	elbOutput, err := c.elbSvc.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{
-		TargetGroupArns: targetGroupArns,
-	})
+	elbSvc, err := c.clients.Elbv2(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("can not describe target groups of service %s/%s: %w", c.clusterName, c.serviceName, err)
+		return nil, err
 	}
 
-	targetGroups := make([]ElbTargetGroup, len(elbOutput.TargetGroups))
+	var elbOutput *elasticloadbalancingv2.DescribeTargetGroupsOutput
 
-	for i, targetGroup := range elbOutput.TargetGroups {
-		if len(targetGroup.LoadBalancerArns) != 1 {
-			return nil, fmt.Errorf("there is more than 1 load balancer for service %s/%s", c.clusterName, c.serviceName)
-		}
+	err = c.clients.retryThrottled(func() error {
+		elbOutput, err = elbSvc.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{
+			TargetGroupArns: targetGroupArns,
+		})
 
-		k := strings.LastIndex(targetGroup.LoadBalancerArns[0], ":")
-		l := strings.LastIndex(*targetGroup.TargetGroupArn, ":")
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not describe target groups of service %s/%s: %w", c.clusterName, c.serviceName, err)
+	}
 
-		targetGroups[i] = ElbTargetGroup{
-			LoadBalancer: targetGroup.LoadBalancerArns[0][k+14:],
-			TargetGroup:  (*targetGroup.TargetGroupArn)[l+1:],
-		}
+	targetGroups, err := newElbTargetGroups(elbOutput.TargetGroups)
+	if err != nil {
+		return nil, fmt.Errorf("can not get target groups of service %s/%s: %w", c.clusterName, c.serviceName, err)
 	}
 
 	return targetGroups, nil
 }
 
 func (c *EcsClient) GetTaskDefinitionName(ctx context.Context) (*string, error) {
-	ecsOutput, err := c.ecsSvc.DescribeServices(ctx, &ecs.DescribeServicesInput{
-		Cluster:  aws.String(c.clusterName),
-		Services: []string{c.serviceName},
-	})
+	service, err := c.clients.DescribeEcsService(ctx, c.clusterName, c.serviceName)
 	if err != nil {
-		return nil, fmt.Errorf("can not describe ecs service %s/%s: %w", c.clusterName, c.serviceName, err)
-	}
-
-	if len(ecsOutput.Services) != 1 {
-		return nil, fmt.Errorf("there was no ecs service %s/%s found", c.clusterName, c.serviceName)
+		return nil, err
 	}
 
-	taskDefinitionRevisionArn := ecsOutput.Services[0].TaskDefinition
+	taskDefinitionRevisionArn := service.TaskDefinition
 	if taskDefinitionRevisionArn == nil {
 		return nil, fmt.Errorf("task definition could not be read from service")
 	}
@@ -129,3 +94,43 @@ func (c *EcsClient) GetClusterName() string {
 func (c *EcsClient) GetServiceName() string {
 	return c.serviceName
 }
+
+// GetContainerNames returns the names of all containers of the task definition revision the service is running with,
+// except for the containers matching one of the exclude expressions
+func (c *EcsClient) GetContainerNames(ctx context.Context, excludes []*regexp.Regexp) ([]string, error) {
+	service, err := c.clients.DescribeEcsService(ctx, c.clusterName, c.serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if service.TaskDefinition == nil {
+		return nil, fmt.Errorf("task definition could not be read from service")
+	}
+
+	taskDefinition, err := c.clients.DescribeEcsTaskDefinition(ctx, *service.TaskDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]string, 0, len(taskDefinition.ContainerDefinitions))
+
+	for _, container := range taskDefinition.ContainerDefinitions {
+		if container.Name == nil || matchesAny(*container.Name, excludes) {
+			continue
+		}
+
+		containers = append(containers, *container.Name)
+	}
+
+	return containers, nil
+}
+
+func matchesAny(value string, expressions []*regexp.Regexp) bool {
+	for _, expression := range expressions {
+		if expression.MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
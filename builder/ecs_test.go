@@ -2,9 +2,16 @@ package builder
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,11 +20,316 @@ func TestEcsClient(t *testing.T) {
 	clusterName := "cluster"
 	serviceName := "service"
 
-	client, err := NewEcsClient(context.Background(), clusterName, serviceName)
-	assert.NoError(t, err)
+	client := NewEcsClient(NewAwsClients(AwsSettings{}), clusterName, serviceName)
 
 	balancers, err := client.GetElbTargetGroups(context.Background())
 	assert.NoError(t, err)
 
 	fmt.Println(balancers)
 }
+
+func TestEcsClientCustomEndpoint(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	var target string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		target = request.Header.Get("X-Amz-Target")
+
+		writer.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		_, err := writer.Write([]byte(`{"services":[{"serviceName":"service","taskDefinition":"arn:aws:ecs:eu-central-1:123456789012:task-definition/prj-env-fam-grp-app:42"}]}`))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	settings := AwsSettings{
+		Region: "eu-central-1",
+		Endpoints: AwsEndpointSettings{
+			Ecs: ts.URL,
+		},
+	}
+
+	client := NewEcsClient(NewAwsClients(settings), "cluster", "service")
+
+	name, err := client.GetTaskDefinitionName(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "prj-env-fam-grp-app", *name)
+	assert.Equal(t, "AmazonEC2ContainerServiceV20141113.DescribeServices", target)
+}
+
+type describeServicesRequest struct {
+	Cluster  string   `json:"cluster"`
+	Services []string `json:"services"`
+}
+
+func provideEcsStandIn(t *testing.T, handler func(request describeServicesRequest) (int, string)) AwsSettings {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_MAX_ATTEMPTS", "1")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		input := describeServicesRequest{}
+		assert.NoError(t, json.NewDecoder(request.Body).Decode(&input))
+
+		status, body := handler(input)
+
+		writer.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		writer.WriteHeader(status)
+		_, err := writer.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(ts.Close)
+
+	return AwsSettings{
+		Region: "eu-central-1",
+		Endpoints: AwsEndpointSettings{
+			Ecs: ts.URL,
+		},
+	}
+}
+
+func TestAwsClientsDescribeEcsServiceBatchesAndCaches(t *testing.T) {
+	lck := sync.Mutex{}
+	requests := make([]describeServicesRequest, 0)
+
+	settings := provideEcsStandIn(t, func(request describeServicesRequest) (int, string) {
+		lck.Lock()
+		requests = append(requests, request)
+		lck.Unlock()
+
+		services := make([]map[string]string, len(request.Services))
+		for i, service := range request.Services {
+			services[i] = map[string]string{
+				"serviceName":    service,
+				"taskDefinition": fmt.Sprintf("arn:aws:ecs:eu-central-1:123456789012:task-definition/%s:1", service),
+			}
+		}
+
+		body, err := json.Marshal(map[string]any{"services": services})
+		assert.NoError(t, err)
+
+		return http.StatusOK, string(body)
+	})
+
+	clients := NewAwsClients(settings)
+	serviceNames := []string{"svc-a", "svc-b", "svc-c"}
+
+	wg := sync.WaitGroup{}
+	for _, serviceName := range serviceNames {
+		wg.Add(1)
+
+		go func(serviceName string) {
+			defer wg.Done()
+
+			client := NewEcsClient(clients, "cluster", serviceName)
+
+			targetGroups, err := client.GetElbTargetGroups(context.Background())
+			assert.NoError(t, err)
+			assert.Empty(t, targetGroups)
+
+			name, err := client.GetTaskDefinitionName(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, serviceName, *name)
+		}(serviceName)
+	}
+	wg.Wait()
+
+	_, err := NewEcsClient(clients, "cluster", "svc-a").GetTaskDefinitionName(context.Background())
+	assert.NoError(t, err)
+
+	assert.Len(t, requests, 1)
+	sort.Strings(requests[0].Services)
+	assert.Equal(t, "cluster", requests[0].Cluster)
+	assert.Equal(t, serviceNames, requests[0].Services)
+}
+
+func TestAwsClientsDescribeEcsServiceMissing(t *testing.T) {
+	settings := provideEcsStandIn(t, func(_ describeServicesRequest) (int, string) {
+		return http.StatusOK, `{"services":[],"failures":[{"reason":"MISSING"}]}`
+	})
+
+	_, err := NewAwsClients(settings).DescribeEcsService(context.Background(), "cluster", "service")
+	assert.EqualError(t, err, "there was no ecs service cluster/service found")
+}
+
+func TestAwsClientsDescribeEcsServiceThrottled(t *testing.T) {
+	attempts := 0
+
+	settings := provideEcsStandIn(t, func(_ describeServicesRequest) (int, string) {
+		attempts++
+
+		if attempts < 3 {
+			return http.StatusBadRequest, `{"__type":"ThrottlingException","message":"Rate exceeded"}`
+		}
+
+		return http.StatusOK, `{"services":[{"serviceName":"service"}]}`
+	})
+
+	clients := NewAwsClients(settings, func(bo *backoff.ExponentialBackOff) {
+		bo.InitialInterval = time.Millisecond
+		bo.MaxInterval = time.Millisecond * 10
+	})
+
+	service, err := clients.DescribeEcsService(context.Background(), "cluster", "service")
+	assert.NoError(t, err)
+	assert.Equal(t, "service", *service.ServiceName)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestEcsClientGetContainerNames(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	taskDefinitionCalls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var body string
+
+		switch request.Header.Get("X-Amz-Target") {
+		case "AmazonEC2ContainerServiceV20141113.DescribeServices":
+			body = `{"services":[{"serviceName":"service","taskDefinition":"arn:aws:ecs:eu-central-1:123456789012:task-definition/app:3"}]}`
+		case "AmazonEC2ContainerServiceV20141113.DescribeTaskDefinition":
+			taskDefinitionCalls++
+			body = `{"taskDefinition":{"containerDefinitions":[{"name":"app"},{"name":"log_router"},{"name":"datadog-agent"}]}}`
+		}
+
+		writer.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		_, err := writer.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	settings := AwsSettings{
+		Region: "eu-central-1",
+		Endpoints: AwsEndpointSettings{
+			Ecs: ts.URL,
+		},
+	}
+	client := NewEcsClient(NewAwsClients(settings), "cluster", "service")
+
+	containers, err := client.GetContainerNames(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app", "log_router", "datadog-agent"}, containers)
+
+	excludes, err := compileExcludeContainers([]string{"^log_router$", "datadog"})
+	assert.NoError(t, err)
+
+	containers, err = client.GetContainerNames(context.Background(), excludes)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app"}, containers)
+	assert.Equal(t, 1, taskDefinitionCalls)
+
+	_, err = compileExcludeContainers([]string{"("})
+	assert.Error(t, err)
+}
+
+func TestAwsClientsDescribeEcsServiceErrorNotCached(t *testing.T) {
+	attempts := 0
+
+	settings := provideEcsStandIn(t, func(_ describeServicesRequest) (int, string) {
+		attempts++
+
+		if attempts == 1 {
+			return http.StatusBadRequest, `{"__type":"AccessDeniedException","message":"denied"}`
+		}
+
+		return http.StatusOK, `{"services":[{"serviceName":"service"}]}`
+	})
+
+	clients := NewAwsClients(settings)
+
+	_, err := clients.DescribeEcsService(context.Background(), "cluster", "service")
+	assert.ErrorContains(t, err, "can not describe ecs service cluster/service")
+
+	service, err := clients.DescribeEcsService(context.Background(), "cluster", "service")
+	assert.NoError(t, err)
+	assert.Equal(t, "service", *service.ServiceName)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestAwsClientsDescribeEcsTaskDefinitionContext(t *testing.T) {
+	settings := provideEcsStandIn(t, func(_ describeServicesRequest) (int, string) {
+		return http.StatusOK, `{"taskDefinition":{"containerDefinitions":[{"name":"app"}]}}`
+	})
+
+	clients := NewAwsClients(settings)
+	arn := "arn:aws:ecs:eu-central-1:123456789012:task-definition/app:3"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := clients.DescribeEcsTaskDefinition(ctx, arn)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	taskDefinition, err := clients.DescribeEcsTaskDefinition(context.Background(), arn)
+	assert.NoError(t, err)
+	assert.Equal(t, "app", *taskDefinition.ContainerDefinitions[0].Name)
+}
+
+func TestAwsClientsDescribeEcsServiceBatchDetachedFromCaller(t *testing.T) {
+	settings := provideEcsStandIn(t, func(request describeServicesRequest) (int, string) {
+		services := make([]map[string]string, len(request.Services))
+		for i, service := range request.Services {
+			services[i] = map[string]string{"serviceName": service}
+		}
+
+		body, err := json.Marshal(map[string]any{"services": services})
+		assert.NoError(t, err)
+
+		return http.StatusOK, string(body)
+	})
+
+	clients := NewAwsClients(settings)
+
+	// the read starting the batch gives up before the batch is sent
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := clients.DescribeEcsService(ctx, "cluster", "svc-a")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	service, err := clients.DescribeEcsService(context.Background(), "cluster", "svc-b")
+	assert.NoError(t, err)
+	assert.Equal(t, "svc-b", *service.ServiceName)
+
+	service, err = clients.DescribeEcsService(context.Background(), "cluster", "svc-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "svc-a", *service.ServiceName)
+}
+
+func TestEcsFargateDiscoverResourcesSkipsContainers(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	targets := make([]string, 0)
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		targets = append(targets, request.Header.Get("X-Amz-Target"))
+
+		writer.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		_, err := writer.Write([]byte(`{"services":[{"serviceName":"grp-app","taskDefinition":"arn:aws:ecs:eu-central-1:123456789012:task-definition/app:3"}]}`))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	settings := OrchestratorSettings{
+		AwsClients: NewAwsClients(AwsSettings{
+			Region: "eu-central-1",
+			Endpoints: AwsEndpointSettings{
+				Ecs: ts.URL,
+			},
+		}),
+		NamePatterns: OrchestratorNamePatterns{
+			EcsCluster: "{env}",
+			EcsService: "{group}-{app}",
+		},
+	}
+	appId := AppId{Project: "prj", Environment: "env", Family: "fam", Group: "grp", Application: "app"}
+
+	resourceNames := &ResourceNames{}
+	err := ecsFargateOrchestrator{}.DiscoverResources(context.Background(), settings, appId, resourceNames)
+	assert.NoError(t, err)
+
+	assert.False(t, ecsFargateOrchestrator{}.UsesContainers())
+	assert.Equal(t, "app", resourceNames.EcsTaskDefinition)
+	assert.Empty(t, resourceNames.Containers)
+	assert.Equal(t, []string{"AmazonEC2ContainerServiceV20141113.DescribeServices"}, targets)
+}
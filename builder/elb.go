@@ -0,0 +1,34 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	elbTypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+// ElbTargetGroup contains the values of the LoadBalancer and TargetGroup dimensions of the AWS/ApplicationELB metrics
+type ElbTargetGroup struct {
+	LoadBalancer string
+	TargetGroup  string
+}
+
+func newElbTargetGroups(describedTargetGroups []elbTypes.TargetGroup) ([]ElbTargetGroup, error) {
+	targetGroups := make([]ElbTargetGroup, len(describedTargetGroups))
+
+	for i, targetGroup := range describedTargetGroups {
+		if len(targetGroup.LoadBalancerArns) != 1 {
+			return nil, fmt.Errorf("there is more than 1 load balancer for target group %s", *targetGroup.TargetGroupArn)
+		}
+
+		k := strings.LastIndex(targetGroup.LoadBalancerArns[0], ":")
+		l := strings.LastIndex(*targetGroup.TargetGroupArn, ":")
+
+		targetGroups[i] = ElbTargetGroup{
+			LoadBalancer: targetGroup.LoadBalancerArns[0][k+14:],
+			TargetGroup:  (*targetGroup.TargetGroupArn)[l+1:],
+		}
+	}
+
+	return targetGroups, nil
+}
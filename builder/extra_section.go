@@ -0,0 +1,173 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	ExtraSectionPositionTop                = "top"
+	ExtraSectionPositionAfterResourceUsage = "after_resource_usage"
+	ExtraSectionPositionAfterErrors        = "after_errors"
+	ExtraSectionPositionAfterHttpServers   = "after_http_servers"
+	ExtraSectionPositionBottom             = "bottom"
+
+	ExtraPanelDatasourceCloudWatch = "cloudwatch"
+	ExtraPanelDatasourcePrometheus = "prometheus"
+)
+
+var extraSectionPositions = []string{
+	ExtraSectionPositionTop,
+	ExtraSectionPositionAfterResourceUsage,
+	ExtraSectionPositionAfterErrors,
+	ExtraSectionPositionAfterHttpServers,
+	ExtraSectionPositionBottom,
+}
+
+// ExtraSection is a section supplied by the user which is rendered next to the generated ones
+type ExtraSection struct {
+	Title     string
+	Position  string
+	Collapsed bool
+	Panels    []ExtraPanel
+}
+
+// ExtraPanel is either the raw json of a grafana panel or a simplified panel spec
+type ExtraPanel struct {
+	RawJson string
+	// Datasource is "prometheus", "cloudwatch" for cloudwatch metric math or the name of a prometheus compatible grafana datasource
+	Datasource string
+	Queries    []string
+	Title      string
+	Type       string
+	Unit       string
+}
+
+func ParseExtraSectionPosition(position string) (string, error) {
+	if position == "" {
+		return ExtraSectionPositionBottom, nil
+	}
+
+	for _, candidate := range extraSectionPositions {
+		if candidate == position {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("'%s' is not a valid position of an extra section, choose between %v", position, extraSectionPositions)
+}
+
+// NewPanelExtra creates the factory of a panel supplied by the user. The placeholders of the app id are replaced in
+// the raw json as well as in the title and queries of a panel spec.
+func NewPanelExtra(extra ExtraPanel, appId AppId) (PanelFactory, error) {
+	if extra.RawJson != "" {
+		return newPanelExtraRaw(Augment(extra.RawJson, appId))
+	}
+
+	if len(extra.Queries) == 0 {
+		return nil, fmt.Errorf("the panel %q needs either raw json or at least one query", extra.Title)
+	}
+
+	queries := make([]string, len(extra.Queries))
+	for i, query := range extra.Queries {
+		queries[i] = Augment(query, appId)
+	}
+
+	title := Augment(extra.Title, appId)
+
+	return func(settings PanelSettings) Panel {
+		panelType := extra.Type
+		if panelType == "" {
+			panelType = "timeseries"
+		}
+
+		datasource, targets := newPanelExtraTargets(settings, extra.Datasource, queries)
+
+		return Panel{
+			Datasource: datasource,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Unit: extra.Unit,
+				},
+				Overrides: []PanelFieldConfigOverride{},
+			},
+			GridPos: settings.gridPos,
+			Targets: targets,
+			Options: &PanelOptionsCloudWatch{},
+			Title:   title,
+			Type:    panelType,
+		}
+	}, nil
+}
+
+func newPanelExtraTargets(settings PanelSettings, datasource string, queries []string) (string, []any) {
+	targets := make([]any, len(queries))
+
+	for i, query := range queries {
+		refId := string(rune('A' + i%26))
+
+		switch datasource {
+		case ExtraPanelDatasourceCloudWatch:
+			targets[i] = PanelTargetCloudWatch{
+				Dimensions: map[string]string{},
+				Expression: query,
+				Id:         fmt.Sprintf("q%d", i),
+				RefId:      refId,
+				Region:     "default",
+				Statistics: []string{},
+			}
+		default:
+			targets[i] = PanelTargetPrometheus{
+				Exemplar:   true,
+				Expression: query,
+				RefId:      refId,
+			}
+		}
+	}
+
+	switch datasource {
+	case ExtraPanelDatasourceCloudWatch:
+		return settings.resourceNames.GrafanaCloudWatchDatasourceName, targets
+	case ExtraPanelDatasourcePrometheus, "":
+		return datasourcePrometheus, targets
+	default:
+		return datasource, targets
+	}
+}
+
+func newPanelExtraRaw(rawJson string) (PanelFactory, error) {
+	raw := make(map[string]any)
+	if err := json.Unmarshal([]byte(rawJson), &raw); err != nil {
+		return nil, fmt.Errorf("can not decode the raw json of the panel: %w", err)
+	}
+
+	var gridPos struct {
+		GridPos PanelGridPos `json:"gridPos"`
+	}
+
+	if err := json.Unmarshal([]byte(rawJson), &gridPos); err != nil {
+		return nil, fmt.Errorf("can not decode the grid position of the panel: %w", err)
+	}
+
+	title, _ := raw["title"].(string)
+	panelType, _ := raw["type"].(string)
+
+	return func(settings PanelSettings) Panel {
+		// only the size is taken from the raw panel, the position is managed by the layout
+		panelGridPos := settings.gridPos
+		if gridPos.GridPos.W > 0 {
+			panelGridPos.W = gridPos.GridPos.W
+		}
+
+		if gridPos.GridPos.H > 0 {
+			panelGridPos.H = gridPos.GridPos.H
+		}
+
+		return Panel{
+			GridPos: panelGridPos,
+			Title:   title,
+			Type:    panelType,
+			Raw:     raw,
+		}
+	}, nil
+}
@@ -0,0 +1,95 @@
+package builder_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtraSection(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		GrafanaCloudWatchDatasourceName: "cw",
+		Containers:                      []string{"app"},
+	}
+
+	db := newDashboardBuilder(t, resourceNames, "ecs")
+	err := db.AddExtraSection(builder.ExtraSection{
+		Title: "Business {app}",
+		Panels: []builder.ExtraPanel{
+			{
+				Title:   "Orders of {group}",
+				Queries: []string{`sum(rate(orders_total{app="{app}"}[5m]))`},
+				Unit:    "short",
+			},
+			{
+				Datasource: "cloudwatch",
+				Title:      "Revenue",
+				Queries:    []string{`SUM(SEARCH('{MyApp,Currency} {env}', 'Sum'))`},
+			},
+			{
+				RawJson: `{"type":"stat","title":"Raw {app}","description":"kept","gridPos":{"h":4,"w":6,"x":17,"y":99},"id":1,"transformations":[{"id":"reduce"}]}`,
+			},
+		},
+	}, provideAppId())
+	assert.NoError(t, err)
+
+	dashboard := db.Build("")
+
+	assert.Len(t, dashboard.Panels, 4)
+	assert.Equal(t, "Business app", dashboard.Panels[0].Title)
+
+	spec := dashboard.Panels[1]
+	assert.Equal(t, "Orders of grp", spec.Title)
+	assert.Equal(t, "prometheus", spec.Datasource)
+	assert.Equal(t, "short", spec.FieldConfig.Defaults.Unit)
+	assert.Equal(t, `sum(rate(orders_total{app="app"}[5m]))`, spec.Targets[0].(builder.PanelTargetPrometheus).Expression)
+
+	cloudwatch := dashboard.Panels[2]
+	assert.Equal(t, "cw", cloudwatch.Datasource)
+	assert.Equal(t, `SUM(SEARCH('{MyApp,Currency} env', 'Sum'))`, cloudwatch.Targets[0].(builder.PanelTargetCloudWatch).Expression)
+
+	body, err := json.Marshal(dashboard.Panels[3])
+	assert.NoError(t, err)
+
+	raw := make(map[string]any)
+	assert.NoError(t, json.Unmarshal(body, &raw))
+
+	// the raw panel is kept as it is, apart from the id and the position which is managed by the layout
+	assert.Equal(t, "Raw app", raw["title"])
+	assert.Equal(t, "kept", raw["description"])
+	assert.Equal(t, []any{map[string]any{"id": "reduce"}}, raw["transformations"])
+	assert.Equal(t, map[string]any{"h": float64(4), "w": float64(6), "x": float64(0), "y": float64(9)}, raw["gridPos"])
+	assert.Equal(t, float64(dashboard.Panels[3].Id), raw["id"])
+	assert.NotEqual(t, float64(1), raw["id"])
+}
+
+func TestExtraSectionInvalidPanels(t *testing.T) {
+	db := newDashboardBuilder(t, &builder.ResourceNames{}, "ecs")
+
+	err := db.AddExtraSection(builder.ExtraSection{
+		Title:  "Broken",
+		Panels: []builder.ExtraPanel{{RawJson: `{"type":`}},
+	}, provideAppId())
+	assert.ErrorContains(t, err, `can not create panel 0 of the section "Broken"`)
+
+	err = db.AddExtraSection(builder.ExtraSection{
+		Title:  "Empty",
+		Panels: []builder.ExtraPanel{{Title: "nothing"}},
+	}, provideAppId())
+	assert.EqualError(t, err, `can not create panel 0 of the section "Empty": the panel "nothing" needs either raw json or at least one query`)
+}
+
+func TestParseExtraSectionPosition(t *testing.T) {
+	position, err := builder.ParseExtraSectionPosition("")
+	assert.NoError(t, err)
+	assert.Equal(t, builder.ExtraSectionPositionBottom, position)
+
+	position, err = builder.ParseExtraSectionPosition("after_errors")
+	assert.NoError(t, err)
+	assert.Equal(t, builder.ExtraSectionPositionAfterErrors, position)
+
+	_, err = builder.ParseExtraSectionPosition("middle")
+	assert.EqualError(t, err, "'middle' is not a valid position of an extra section, choose between [top after_resource_usage after_errors after_http_servers bottom]")
+}
@@ -0,0 +1,187 @@
+package builder
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/thoas/go-funk"
+)
+
+var dashboardSections = []string{
+	DashboardSectionOverview,
+	DashboardSectionResources,
+	DashboardSectionErrors,
+	DashboardSectionLogs,
+	DashboardSectionElb,
+	DashboardSectionTraefik,
+	DashboardSectionHttpRoutes,
+	DashboardSectionStreamConsumer,
+	DashboardSectionStreamProducer,
+	DashboardSectionKinesis,
+	DashboardSectionSqs,
+	DashboardSectionDdb,
+	DashboardSectionSns,
+}
+
+type DashboardFilterSettings struct {
+	// IncludeSections restricts the dashboard to the given sections, all sections are included if empty
+	IncludeSections []string
+	ExcludeSections []string
+	RoutePaths      NameFilterSettings
+	RouteMethods    NameFilterSettings
+	QueueNames      NameFilterSettings
+	TableNames      NameFilterSettings
+	StreamNames     NameFilterSettings
+	TopicNames      NameFilterSettings
+}
+
+// NameFilterSettings are regular expressions, a name is kept if it matches any include, or there are none, and no exclude
+type NameFilterSettings struct {
+	Include []string
+	Exclude []string
+}
+
+// DashboardFilter decides which sections, routes and resources are rendered. The zero value and nil keep everything.
+type DashboardFilter struct {
+	includeSections []string
+	excludeSections []string
+	routePaths      nameFilter
+	routeMethods    nameFilter
+	queueNames      nameFilter
+	tableNames      nameFilter
+	streamNames     nameFilter
+	topicNames      nameFilter
+}
+
+type nameFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func DashboardSections() []string {
+	return append([]string{}, dashboardSections...)
+}
+
+func NewDashboardFilter(settings DashboardFilterSettings) (*DashboardFilter, error) {
+	var err error
+	filter := &DashboardFilter{}
+
+	for _, sections := range [][]string{settings.IncludeSections, settings.ExcludeSections} {
+		for _, section := range sections {
+			if !funk.ContainsString(dashboardSections, section) {
+				return nil, fmt.Errorf("'%s' is not a valid section, choose between %v", section, dashboardSections)
+			}
+		}
+	}
+
+	filter.includeSections = settings.IncludeSections
+	filter.excludeSections = settings.ExcludeSections
+
+	if filter.routePaths, err = newNameFilter("route path", settings.RoutePaths); err != nil {
+		return nil, err
+	}
+
+	if filter.routeMethods, err = newNameFilter("route method", settings.RouteMethods); err != nil {
+		return nil, err
+	}
+
+	if filter.queueNames, err = newNameFilter("queue name", settings.QueueNames); err != nil {
+		return nil, err
+	}
+
+	if filter.tableNames, err = newNameFilter("table name", settings.TableNames); err != nil {
+		return nil, err
+	}
+
+	if filter.streamNames, err = newNameFilter("stream name", settings.StreamNames); err != nil {
+		return nil, err
+	}
+
+	if filter.topicNames, err = newNameFilter("topic name", settings.TopicNames); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func newNameFilter(kind string, settings NameFilterSettings) (nameFilter, error) {
+	var err error
+	filter := nameFilter{}
+
+	if filter.include, err = compileNameFilterPatterns(kind, settings.Include); err != nil {
+		return filter, err
+	}
+
+	if filter.exclude, err = compileNameFilterPatterns(kind, settings.Exclude); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func compileNameFilterPatterns(kind string, patterns []string) ([]*regexp.Regexp, error) {
+	expressions := make([]*regexp.Regexp, len(patterns))
+
+	for i, pattern := range patterns {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s filter pattern %q: %w", kind, pattern, err)
+		}
+
+		expressions[i] = expression
+	}
+
+	return expressions, nil
+}
+
+func (f nameFilter) matches(name string) bool {
+	for _, expression := range f.exclude {
+		if expression.MatchString(name) {
+			return false
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+
+	for _, expression := range f.include {
+		if expression.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *DashboardFilter) IncludesSection(section string) bool {
+	if f == nil {
+		return true
+	}
+
+	if funk.ContainsString(f.excludeSections, section) {
+		return false
+	}
+
+	return len(f.includeSections) == 0 || funk.ContainsString(f.includeSections, section)
+}
+
+func (f *DashboardFilter) IncludesRoute(handler MetadataHttpServerHandler) bool {
+	return f == nil || f.routePaths.matches(handler.Path) && f.routeMethods.matches(handler.Method)
+}
+
+func (f *DashboardFilter) IncludesQueue(queueName string) bool {
+	return f == nil || f.queueNames.matches(queueName)
+}
+
+func (f *DashboardFilter) IncludesTable(tableName string) bool {
+	return f == nil || f.tableNames.matches(tableName)
+}
+
+func (f *DashboardFilter) IncludesStream(streamName string) bool {
+	return f == nil || f.streamNames.matches(streamName)
+}
+
+func (f *DashboardFilter) IncludesTopic(topicName string) bool {
+	return f == nil || f.topicNames.matches(topicName)
+}
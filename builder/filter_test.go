@@ -0,0 +1,99 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDashboardFilterInvalid(t *testing.T) {
+	_, err := builder.NewDashboardFilter(builder.DashboardFilterSettings{
+		IncludeSections: []string{"lambda"},
+	})
+	assert.ErrorContains(t, err, "'lambda' is not a valid section, choose between [overview resources errors logs")
+
+	_, err = builder.NewDashboardFilter(builder.DashboardFilterSettings{
+		QueueNames: builder.NameFilterSettings{Exclude: []string{"("}},
+	})
+	assert.ErrorContains(t, err, `invalid queue name filter pattern "("`)
+}
+
+func TestDashboardFilterNames(t *testing.T) {
+	filter, err := builder.NewDashboardFilter(builder.DashboardFilterSettings{
+		RoutePaths: builder.NameFilterSettings{
+			Include: []string{"^/v1/", "^/admin"},
+			Exclude: []string{"^/v1/internal"},
+		},
+		RouteMethods: builder.NameFilterSettings{
+			Exclude: []string{"^OPTIONS$"},
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, filter.IncludesRoute(builder.MetadataHttpServerHandler{Method: "GET", Path: "/v1/users"}))
+	assert.True(t, filter.IncludesRoute(builder.MetadataHttpServerHandler{Method: "POST", Path: "/admin/users"}))
+	assert.False(t, filter.IncludesRoute(builder.MetadataHttpServerHandler{Method: "OPTIONS", Path: "/v1/users"}))
+	assert.False(t, filter.IncludesRoute(builder.MetadataHttpServerHandler{Method: "GET", Path: "/v1/internal/sync"}))
+	assert.False(t, filter.IncludesRoute(builder.MetadataHttpServerHandler{Method: "GET", Path: "/health"}))
+
+	// names without patterns are always kept
+	assert.True(t, filter.IncludesQueue("any-queue"))
+
+	var none *builder.DashboardFilter
+	assert.True(t, none.IncludesSection(builder.DashboardSectionSqs))
+	assert.True(t, none.IncludesTable("any-table"))
+}
+
+func TestDashboardFilterSections(t *testing.T) {
+	filter, err := builder.NewDashboardFilter(builder.DashboardFilterSettings{
+		ExcludeSections: []string{builder.DashboardSectionLogs, builder.DashboardSectionResources},
+		TableNames: builder.NameFilterSettings{
+			Include: []string{"-orders$"},
+		},
+		QueueNames: builder.NameFilterSettings{
+			Exclude: []string{"-dlq$"},
+		},
+		TopicNames: builder.NameFilterSettings{
+			Include: []string{"^order-"},
+		},
+	})
+	assert.NoError(t, err)
+
+	db := newDashboardBuilder(t, provideLayoutResourceNames(), "ecs", builder.WithFilter(filter))
+	db.AddServiceAndTask()
+	db.AddErrorsAndWarnings()
+	db.AddDynamoDbTable(builder.MetadataCloudAwsDynamodbTable{TableName: "app-orders"})
+	db.AddDynamoDbTable(builder.MetadataCloudAwsDynamodbTable{TableName: "app-users"})
+	db.AddCloudAwsSqsQueue(builder.MetadataCloudAwsSqsQueue{QueueNameFull: "app-events"})
+	db.AddCloudAwsSqsQueue(builder.MetadataCloudAwsSqsQueue{QueueNameFull: "app-events-dlq"})
+	db.AddCloudAwsSnsTopic(builder.MetadataCloudAwsSnsTopic{TopicName: "order-created"})
+	db.AddCloudAwsSnsTopic(builder.MetadataCloudAwsSnsTopic{TopicName: "user-created"})
+
+	dashboard := db.Build("")
+
+	titles := make([]string, 0)
+	for _, panel := range dashboard.Panels {
+		if panel.Type == "row" {
+			titles = append(titles, panel.Title)
+		}
+	}
+
+	assert.Equal(t, []string{"Errors & Warnings", "Dynamodb: app-orders", "SQS: app-events", "SNS: order-created"}, titles)
+	assert.Len(t, dashboard.Panels, 3+2+4+3+5)
+
+	only, err := builder.NewDashboardFilter(builder.DashboardFilterSettings{
+		IncludeSections: []string{builder.DashboardSectionLogs},
+	})
+	assert.NoError(t, err)
+
+	db = newDashboardBuilder(t, provideLayoutResourceNames(), "ecs", builder.WithFilter(only))
+	db.AddServiceAndTask()
+	db.AddErrorsAndWarnings()
+	db.AddDynamoDbTable(builder.MetadataCloudAwsDynamodbTable{TableName: "app-orders"})
+
+	dashboard = db.Build("")
+
+	assert.Len(t, dashboard.Panels, 2)
+	assert.Equal(t, "Error & Warning Logs", dashboard.Panels[1].Title)
+}
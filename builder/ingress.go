@@ -0,0 +1,82 @@
+package builder
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+const (
+	ingressTraefik                   = "traefik"
+	ingressNginx                     = "nginx"
+	ingressIstio                     = "istio"
+	ingressAwsLoadBalancerController = "aws_lb_controller"
+)
+
+// Ingress is the http edge in front of the pods of a kubernetes workload. It renders the request count, latency,
+// status code and per target panels of the dashboard.
+type Ingress interface {
+	Name() string
+	// Panels are the rows and panels rendered for the ingress
+	Panels(resourceNames *ResourceNames) []PanelFactory
+	// DiscoverResources fills the ingress specific fields of the resource names
+	DiscoverResources(ctx context.Context, settings IngressSettings, appId AppId, resourceNames *ResourceNames) error
+}
+
+type IngressSettings struct {
+	AwsClients   *AwsClients
+	NamePatterns IngressNamePatterns
+}
+
+type IngressNamePatterns struct {
+	NginxIngress string
+	IstioService string
+	// AwsLbTargetGroup looks the target group up by its name, if empty the target groups are looked up by their tags
+	AwsLbTargetGroup  string
+	AwsLbIngressStack string
+	// AwsLbCluster restricts the target groups to the ones of the cluster, if empty the cluster isn't checked
+	AwsLbCluster string
+}
+
+var (
+	ingressesLck = sync.RWMutex{}
+	ingresses    = map[string]Ingress{}
+)
+
+func init() {
+	RegisterIngress(traefikIngress{})
+	RegisterIngress(nginxIngress{})
+	RegisterIngress(istioIngress{})
+	RegisterIngress(awsLoadBalancerControllerIngress{})
+}
+
+// RegisterIngress makes an ingress available by its name, an already registered ingress with the same name is replaced
+func RegisterIngress(ingress Ingress) {
+	ingressesLck.Lock()
+	defer ingressesLck.Unlock()
+
+	ingresses[ingress.Name()] = ingress
+}
+
+func GetIngress(name string) (Ingress, bool) {
+	ingressesLck.RLock()
+	defer ingressesLck.RUnlock()
+
+	ingress, ok := ingresses[name]
+
+	return ingress, ok
+}
+
+func AvailableIngresses() []string {
+	ingressesLck.RLock()
+	defer ingressesLck.RUnlock()
+
+	names := make([]string, 0, len(ingresses))
+	for name := range ingresses {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
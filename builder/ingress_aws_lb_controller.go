@@ -0,0 +1,71 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	awsLbControllerTagCluster = "elbv2.k8s.aws/cluster"
+	awsLbControllerTagStack   = "ingress.k8s.aws/stack"
+)
+
+// awsLoadBalancerControllerIngress renders the cloudwatch metrics of the target groups the aws load balancer controller
+// registers the pods at. The controller names the target groups k8s-<namespace>-<service>-<hash>, so they are looked up
+// by the stack tag of the ingress and optionally the cluster tag. A target group name pattern looks the target group up
+// by its name instead, e.g. for target groups bound through a TargetGroupBinding.
+type awsLoadBalancerControllerIngress struct{}
+
+func (i awsLoadBalancerControllerIngress) Name() string {
+	return ingressAwsLoadBalancerController
+}
+
+func (i awsLoadBalancerControllerIngress) Panels(resourceNames *ResourceNames) []PanelFactory {
+	panels := make([]PanelFactory, 0)
+
+	for targetGroupIndex := range resourceNames.TargetGroups {
+		panels = append(panels,
+			NewPanelRow("Load Balancer"),
+			NewPanelElbRequestCount(targetGroupIndex),
+			NewPanelElbResponseTime(targetGroupIndex),
+			NewPanelElbHttpStatus(targetGroupIndex),
+			NewPanelElbHealthyHosts(targetGroupIndex),
+			NewPanelElbRequestCountPerTarget(targetGroupIndex),
+		)
+	}
+
+	return panels
+}
+
+func (i awsLoadBalancerControllerIngress) DiscoverResources(ctx context.Context, settings IngressSettings, appId AppId, resourceNames *ResourceNames) error {
+	var err error
+	var targetGroups []ElbTargetGroup
+
+	if settings.NamePatterns.AwsLbTargetGroup != "" {
+		targetGroupName := Augment(settings.NamePatterns.AwsLbTargetGroup, appId)
+
+		if targetGroups, err = settings.AwsClients.DescribeElbTargetGroupsByName(ctx, []string{targetGroupName}); err != nil {
+			return fmt.Errorf("can not get target groups: %w", err)
+		}
+
+		resourceNames.TargetGroups = targetGroups
+
+		return nil
+	}
+
+	tags := map[string]string{
+		awsLbControllerTagStack: Augment(settings.NamePatterns.AwsLbIngressStack, appId),
+	}
+
+	if settings.NamePatterns.AwsLbCluster != "" {
+		tags[awsLbControllerTagCluster] = Augment(settings.NamePatterns.AwsLbCluster, appId)
+	}
+
+	if targetGroups, err = settings.AwsClients.DescribeElbTargetGroupsByTags(ctx, tags); err != nil {
+		return fmt.Errorf("can not get target groups: %w", err)
+	}
+
+	resourceNames.TargetGroups = targetGroups
+
+	return nil
+}
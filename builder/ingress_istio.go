@@ -0,0 +1,46 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+)
+
+type istioIngress struct{}
+
+func getIstioServiceLabelFilter(namespace, serviceName string) string {
+	// only the metrics reported by the sidecar of the destination contain the requests of every source
+	return fmt.Sprintf(`reporter="destination", destination_service_namespace=%q, destination_service_name=%q`, namespace, serviceName)
+}
+
+func (i istioIngress) Name() string {
+	return ingressIstio
+}
+
+func (i istioIngress) Panels(_ *ResourceNames) []PanelFactory {
+	metrics := prometheusIngressMetrics{
+		labelFilter: func(resourceNames *ResourceNames) string {
+			return getIstioServiceLabelFilter(resourceNames.KubernetesNamespace, resourceNames.IstioServiceName)
+		},
+		requests:       "istio_requests_total",
+		statusLabel:    "response_code",
+		durationSum:    "istio_request_duration_milliseconds_sum",
+		durationCount:  "istio_request_duration_milliseconds_count",
+		durationBucket: "istio_request_duration_milliseconds_bucket",
+		durationUnit:   "ms",
+	}
+
+	return []PanelFactory{
+		NewPanelRow("Istio"),
+		newPanelIngressRequestCount(metrics),
+		newPanelIngressResponseTime(metrics),
+		newPanelIngressHttpStatus(metrics),
+		NewPanelKubernetesHealthyPods,
+		newPanelIngressRequestCountPerTarget(metrics),
+	}
+}
+
+func (i istioIngress) DiscoverResources(_ context.Context, settings IngressSettings, appId AppId, resourceNames *ResourceNames) error {
+	resourceNames.IstioServiceName = Augment(settings.NamePatterns.IstioService, appId)
+
+	return nil
+}
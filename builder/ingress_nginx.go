@@ -0,0 +1,45 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+)
+
+type nginxIngress struct{}
+
+func getNginxIngressLabelFilter(namespace, ingressName string) string {
+	return fmt.Sprintf(`namespace=%q, ingress=%q`, namespace, ingressName)
+}
+
+func (i nginxIngress) Name() string {
+	return ingressNginx
+}
+
+func (i nginxIngress) Panels(_ *ResourceNames) []PanelFactory {
+	metrics := prometheusIngressMetrics{
+		labelFilter: func(resourceNames *ResourceNames) string {
+			return getNginxIngressLabelFilter(resourceNames.KubernetesNamespace, resourceNames.NginxIngressName)
+		},
+		requests:       "nginx_ingress_controller_requests",
+		statusLabel:    "status",
+		durationSum:    "nginx_ingress_controller_request_duration_seconds_sum",
+		durationCount:  "nginx_ingress_controller_request_duration_seconds_count",
+		durationBucket: "nginx_ingress_controller_request_duration_seconds_bucket",
+		durationUnit:   "s",
+	}
+
+	return []PanelFactory{
+		NewPanelRow("Nginx Ingress"),
+		newPanelIngressRequestCount(metrics),
+		newPanelIngressResponseTime(metrics),
+		newPanelIngressHttpStatus(metrics),
+		NewPanelKubernetesHealthyPods,
+		newPanelIngressRequestCountPerTarget(metrics),
+	}
+}
+
+func (i nginxIngress) DiscoverResources(_ context.Context, settings IngressSettings, appId AppId, resourceNames *ResourceNames) error {
+	resourceNames.NginxIngressName = Augment(settings.NamePatterns.NginxIngress, appId)
+
+	return nil
+}
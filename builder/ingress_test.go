@@ -0,0 +1,301 @@
+package builder_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func provideIngressResourceNames() *builder.ResourceNames {
+	return &builder.ResourceNames{
+		Environment:          "env",
+		KubernetesNamespace:  "prj",
+		KubernetesDeployment: "grp-app",
+		KubernetesPod:        "grp-app",
+		TraefikServiceName:   "prj-grp-app-8080@kubernetes",
+		Containers:           []string{"app"},
+	}
+}
+
+func TestAvailableIngresses(t *testing.T) {
+	assert.Equal(t, []string{"aws_lb_controller", "istio", "nginx", "traefik"}, builder.AvailableIngresses())
+}
+
+func TestIngressPanels(t *testing.T) {
+	tests := []struct {
+		ingress       string
+		rowTitle      string
+		requestCount  string
+		responseTime  string
+		http5xxStatus string
+	}{
+		{
+			ingress:       "traefik",
+			rowTitle:      "Traefik",
+			requestCount:  `sum(irate(traefik_service_requests_total{service="prj-grp-app-8080@kubernetes"}[1m])) * 60`,
+			responseTime:  `sum(irate(traefik_service_request_duration_seconds_sum{service="prj-grp-app-8080@kubernetes"}[$__rate_interval])) / sum(irate(traefik_service_requests_total{service="prj-grp-app-8080@kubernetes"}[$__rate_interval]))`,
+			http5xxStatus: `sum(irate(traefik_service_requests_total{code=~"5.*",service="prj-grp-app-8080@kubernetes"}[1m])) * 60 or vector(0)`,
+		},
+		{
+			ingress:       "nginx",
+			rowTitle:      "Nginx Ingress",
+			requestCount:  `sum(irate(nginx_ingress_controller_requests{namespace="prj", ingress="grp-app"}[1m])) * 60`,
+			responseTime:  `sum(irate(nginx_ingress_controller_request_duration_seconds_sum{namespace="prj", ingress="grp-app"}[$__rate_interval])) / sum(irate(nginx_ingress_controller_request_duration_seconds_count{namespace="prj", ingress="grp-app"}[$__rate_interval]))`,
+			http5xxStatus: `sum(irate(nginx_ingress_controller_requests{status=~"5.*",namespace="prj", ingress="grp-app"}[1m])) * 60 or vector(0)`,
+		},
+		{
+			ingress:       "istio",
+			rowTitle:      "Istio",
+			requestCount:  `sum(irate(istio_requests_total{reporter="destination", destination_service_namespace="prj", destination_service_name="grp-app"}[1m])) * 60`,
+			responseTime:  `sum(irate(istio_request_duration_milliseconds_sum{reporter="destination", destination_service_namespace="prj", destination_service_name="grp-app"}[$__rate_interval])) / sum(irate(istio_request_duration_milliseconds_count{reporter="destination", destination_service_namespace="prj", destination_service_name="grp-app"}[$__rate_interval]))`,
+			http5xxStatus: `sum(irate(istio_requests_total{response_code=~"5.*",reporter="destination", destination_service_namespace="prj", destination_service_name="grp-app"}[1m])) * 60 or vector(0)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rowTitle, func(t *testing.T) {
+			resourceNames := provideIngressResourceNames()
+
+			if ingress, ok := builder.GetIngress(tt.ingress); ok {
+				settings := builder.IngressSettings{
+					NamePatterns: builder.IngressNamePatterns{
+						NginxIngress: "{group}-{app}",
+						IstioService: "{group}-{app}",
+					},
+				}
+
+				err := ingress.DiscoverResources(context.Background(), settings, provideAppId(), resourceNames)
+				assert.NoError(t, err)
+			}
+
+			db := newDashboardBuilder(t, resourceNames, "kubernetes", builder.WithIngress(tt.ingress))
+			db.AddTraefikService()
+			dashboard := db.Build("")
+
+			assert.Len(t, dashboard.Panels, 6)
+			assert.Equal(t, tt.rowTitle, dashboard.Panels[0].Title)
+			assert.Equal(t, tt.requestCount, dashboard.Panels[1].Targets[0].(builder.PanelTargetPrometheus).Expression)
+			assert.Equal(t, tt.responseTime, dashboard.Panels[2].Targets[0].(builder.PanelTargetPrometheus).Expression)
+			assert.Equal(t, tt.http5xxStatus, dashboard.Panels[3].Targets[3].(builder.PanelTargetPrometheus).Expression)
+			assert.Equal(t, "Healthy Endpoints", dashboard.Panels[4].Title)
+			assert.Equal(t, "Requests Per Healthy Target", dashboard.Panels[5].Title)
+		})
+	}
+}
+
+func TestIngressAwsLoadBalancerController(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	var names []string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.NoError(t, request.ParseForm())
+		names = append(names, request.Form.Get("Names.member.1"))
+
+		writer.Header().Set("Content-Type", "text/xml")
+		_, err := writer.Write([]byte(`<DescribeTargetGroupsResponse xmlns="http://elasticloadbalancing.amazonaws.com/doc/2015-12-01/">
+  <DescribeTargetGroupsResult>
+    <TargetGroups>
+      <member>
+        <TargetGroupArn>arn:aws:elasticloadbalancing:eu-central-1:123456789012:targetgroup/prj-grp-app/73e2d6bc24d8a067</TargetGroupArn>
+        <LoadBalancerArns>
+          <member>arn:aws:elasticloadbalancing:eu-central-1:123456789012:loadbalancer/app/k8s-prj/50dc6c495c0c9188</member>
+        </LoadBalancerArns>
+      </member>
+    </TargetGroups>
+  </DescribeTargetGroupsResult>
+</DescribeTargetGroupsResponse>`))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clients := builder.NewAwsClients(builder.AwsSettings{
+		Region: "eu-central-1",
+		Endpoints: builder.AwsEndpointSettings{
+			Elbv2: ts.URL,
+		},
+	})
+
+	ingress, ok := builder.GetIngress("aws_lb_controller")
+	assert.True(t, ok)
+
+	settings := builder.IngressSettings{
+		AwsClients: clients,
+		NamePatterns: builder.IngressNamePatterns{
+			AwsLbTargetGroup: "{project}-{group}-{app}",
+		},
+	}
+
+	resourceNames := provideIngressResourceNames()
+	err := ingress.DiscoverResources(context.Background(), settings, provideAppId(), resourceNames)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"prj-grp-app"}, names)
+	assert.Equal(t, []builder.ElbTargetGroup{
+		{
+			LoadBalancer: "app/k8s-prj/50dc6c495c0c9188",
+			TargetGroup:  "targetgroup/prj-grp-app/73e2d6bc24d8a067",
+		},
+	}, resourceNames.TargetGroups)
+
+	db := newDashboardBuilder(t, resourceNames, "kubernetes", builder.WithIngress("aws_lb_controller"))
+	db.AddElbTargetGroups()
+	db.AddIngress()
+	dashboard := db.Build("")
+
+	// the target groups are only rendered once as part of the ingress
+	assert.Len(t, dashboard.Panels, 6)
+	assert.Equal(t, "Load Balancer", dashboard.Panels[0].Title)
+	assert.Equal(t, "AWS/ApplicationELB", dashboard.Panels[1].Targets[0].(builder.PanelTargetCloudWatch).Namespace)
+}
+
+func TestIngressAwsLoadBalancerControllerTargetGroupNotFound(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/xml")
+		writer.WriteHeader(http.StatusBadRequest)
+		_, err := writer.Write([]byte(`<ErrorResponse xmlns="http://elasticloadbalancing.amazonaws.com/doc/2015-12-01/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>TargetGroupNotFound</Code>
+    <Message>One or more target groups not found</Message>
+  </Error>
+</ErrorResponse>`))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clients := builder.NewAwsClients(builder.AwsSettings{
+		Region: "eu-central-1",
+		Endpoints: builder.AwsEndpointSettings{
+			Elbv2: ts.URL,
+		},
+	})
+
+	ingress, _ := builder.GetIngress("aws_lb_controller")
+	settings := builder.IngressSettings{
+		AwsClients: clients,
+		NamePatterns: builder.IngressNamePatterns{
+			AwsLbTargetGroup: "{project}-{group}-{app}",
+		},
+	}
+
+	resourceNames := provideIngressResourceNames()
+	err := ingress.DiscoverResources(context.Background(), settings, provideAppId(), resourceNames)
+	assert.NoError(t, err)
+	assert.Empty(t, resourceNames.TargetGroups)
+}
+
+func TestIngressAwsLoadBalancerControllerTargetGroupTags(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	var actions []string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.NoError(t, request.ParseForm())
+		actions = append(actions, request.Form.Get("Action"))
+
+		var body string
+		switch request.Form.Get("Action") {
+		case "DescribeTargetGroups":
+			body = `<DescribeTargetGroupsResponse xmlns="http://elasticloadbalancing.amazonaws.com/doc/2015-12-01/">
+  <DescribeTargetGroupsResult>
+    <TargetGroups>
+      <member>
+        <TargetGroupArn>arn:aws:elasticloadbalancing:eu-central-1:123456789012:targetgroup/k8s-prj-grpapp-0c8e4a3f5d/73e2d6bc24d8a067</TargetGroupArn>
+        <LoadBalancerArns>
+          <member>arn:aws:elasticloadbalancing:eu-central-1:123456789012:loadbalancer/app/k8s-prj/50dc6c495c0c9188</member>
+        </LoadBalancerArns>
+      </member>
+      <member>
+        <TargetGroupArn>arn:aws:elasticloadbalancing:eu-central-1:123456789012:targetgroup/k8s-prj-grpother-5d1b2e7f9a/1f2e3d4c5b6a7988</TargetGroupArn>
+        <LoadBalancerArns>
+          <member>arn:aws:elasticloadbalancing:eu-central-1:123456789012:loadbalancer/app/k8s-prj/50dc6c495c0c9188</member>
+        </LoadBalancerArns>
+      </member>
+      <member>
+        <TargetGroupArn>arn:aws:elasticloadbalancing:eu-central-1:123456789012:targetgroup/k8s-prj-grpapp-9a8b7c6d5e/0a1b2c3d4e5f6a7b</TargetGroupArn>
+        <LoadBalancerArns>
+          <member>arn:aws:elasticloadbalancing:eu-central-1:123456789012:loadbalancer/app/k8s-prj/7d6c5b4a39281706</member>
+        </LoadBalancerArns>
+      </member>
+    </TargetGroups>
+  </DescribeTargetGroupsResult>
+</DescribeTargetGroupsResponse>`
+		case "DescribeTags":
+			body = `<DescribeTagsResponse xmlns="http://elasticloadbalancing.amazonaws.com/doc/2015-12-01/">
+  <DescribeTagsResult>
+    <TagDescriptions>
+      <member>
+        <ResourceArn>arn:aws:elasticloadbalancing:eu-central-1:123456789012:targetgroup/k8s-prj-grpapp-0c8e4a3f5d/73e2d6bc24d8a067</ResourceArn>
+        <Tags>
+          <member><Key>elbv2.k8s.aws/cluster</Key><Value>env</Value></member>
+          <member><Key>ingress.k8s.aws/stack</Key><Value>prj/grp-app</Value></member>
+        </Tags>
+      </member>
+      <member>
+        <ResourceArn>arn:aws:elasticloadbalancing:eu-central-1:123456789012:targetgroup/k8s-prj-grpother-5d1b2e7f9a/1f2e3d4c5b6a7988</ResourceArn>
+        <Tags>
+          <member><Key>elbv2.k8s.aws/cluster</Key><Value>env</Value></member>
+          <member><Key>ingress.k8s.aws/stack</Key><Value>prj/grp-other</Value></member>
+        </Tags>
+      </member>
+      <member>
+        <ResourceArn>arn:aws:elasticloadbalancing:eu-central-1:123456789012:targetgroup/k8s-prj-grpapp-9a8b7c6d5e/0a1b2c3d4e5f6a7b</ResourceArn>
+        <Tags>
+          <member><Key>elbv2.k8s.aws/cluster</Key><Value>other-env</Value></member>
+          <member><Key>ingress.k8s.aws/stack</Key><Value>prj/grp-app</Value></member>
+        </Tags>
+      </member>
+    </TagDescriptions>
+  </DescribeTagsResult>
+</DescribeTagsResponse>`
+		}
+
+		writer.Header().Set("Content-Type", "text/xml")
+		_, err := writer.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clients := builder.NewAwsClients(builder.AwsSettings{
+		Region: "eu-central-1",
+		Endpoints: builder.AwsEndpointSettings{
+			Elbv2: ts.URL,
+		},
+	})
+
+	ingress, _ := builder.GetIngress("aws_lb_controller")
+	settings := builder.IngressSettings{
+		AwsClients: clients,
+		NamePatterns: builder.IngressNamePatterns{
+			AwsLbIngressStack: "{project}/{group}-{app}",
+			AwsLbCluster:      "{env}",
+		},
+	}
+
+	resourceNames := provideIngressResourceNames()
+	err := ingress.DiscoverResources(context.Background(), settings, provideAppId(), resourceNames)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []builder.ElbTargetGroup{
+		{
+			LoadBalancer: "app/k8s-prj/50dc6c495c0c9188",
+			TargetGroup:  "targetgroup/k8s-prj-grpapp-0c8e4a3f5d/73e2d6bc24d8a067",
+		},
+	}, resourceNames.TargetGroups)
+
+	// the target groups and their tags are only described once
+	resourceNames = provideIngressResourceNames()
+	err = ingress.DiscoverResources(context.Background(), settings, provideAppId(), resourceNames)
+	assert.NoError(t, err)
+
+	assert.Len(t, resourceNames.TargetGroups, 1)
+	assert.Equal(t, []string{"DescribeTargetGroups", "DescribeTags"}, actions)
+}
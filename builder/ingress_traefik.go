@@ -0,0 +1,25 @@
+package builder
+
+import "context"
+
+type traefikIngress struct{}
+
+func (i traefikIngress) Name() string {
+	return ingressTraefik
+}
+
+func (i traefikIngress) Panels(_ *ResourceNames) []PanelFactory {
+	return []PanelFactory{
+		NewPanelRow("Traefik"),
+		NewPanelTraefikRequestCount,
+		NewPanelTraefikResponseTime,
+		NewPanelTraefikHttpStatus,
+		NewPanelKubernetesHealthyPods,
+		NewPanelTraefikRequestCountPerTarget,
+	}
+}
+
+// DiscoverResources has nothing to do, as the traefik service name is already known to the kubernetes orchestrator
+func (i traefikIngress) DiscoverResources(_ context.Context, _ IngressSettings, _ AppId, _ *ResourceNames) error {
+	return nil
+}
@@ -0,0 +1,35 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalCanonicalJSON encodes the value with the keys of all objects sorted, regardless whether they originate from a
+// struct or a map, and without escaping html characters like < and > in the queries. The same value always results
+// in the same bytes, so the output can be compared and hashed.
+func MarshalCanonicalJSON(value any) ([]byte, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("can not encode value: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("can not decode value: %w", err)
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(generic); err != nil {
+		return nil, fmt.Errorf("can not encode value: %w", err)
+	}
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
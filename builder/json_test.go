@@ -0,0 +1,47 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalCanonicalJSON(t *testing.T) {
+	value := struct {
+		Zebra      string            `json:"zebra"`
+		Alpha      int               `json:"alpha"`
+		Dimensions map[string]string `json:"dimensions"`
+		Expression string            `json:"expr"`
+	}{
+		Zebra: "z",
+		Alpha: 2147483647,
+		Dimensions: map[string]string{
+			"TopicName": "topic",
+			"Method":    "GET",
+		},
+		Expression: `up > 0 && down < 1`,
+	}
+
+	body, err := builder.MarshalCanonicalJSON(value)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"alpha":2147483647,"dimensions":{"Method":"GET","TopicName":"topic"},"expr":"up > 0 && down < 1","zebra":"z"}`, string(body))
+}
+
+func TestMarshalCanonicalJSONDashboard(t *testing.T) {
+	build := func() []byte {
+		db := newDashboardBuilder(t, &builder.ResourceNames{Containers: []string{"app"}}, "ecs", builder.WithTemplating())
+		db.AddServiceAndTask()
+		db.AddCloudAwsSnsTopic(builder.MetadataCloudAwsSnsTopic{TopicName: "topic"})
+
+		body, err := builder.MarshalCanonicalJSON(db.Build("dashboard"))
+		assert.NoError(t, err)
+
+		return body
+	}
+
+	expected := build()
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, string(expected), string(build()))
+	}
+}
@@ -0,0 +1,413 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/thoas/go-funk"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	KubernetesWorkloadKindDeployment  = "Deployment"
+	KubernetesWorkloadKindStatefulSet = "StatefulSet"
+	KubernetesWorkloadKindDaemonSet   = "DaemonSet"
+	KubernetesWorkloadKindRollout     = "Rollout"
+)
+
+type kubernetesWorkloadResource struct {
+	kind     string
+	apiPath  string
+	resource string
+}
+
+// the order is the order in which the kinds are tried if the kind of a workload is unknown
+var kubernetesWorkloadResources = []kubernetesWorkloadResource{
+	{kind: KubernetesWorkloadKindDeployment, apiPath: "/apis/apps/v1", resource: "deployments"},
+	{kind: KubernetesWorkloadKindStatefulSet, apiPath: "/apis/apps/v1", resource: "statefulsets"},
+	{kind: KubernetesWorkloadKindDaemonSet, apiPath: "/apis/apps/v1", resource: "daemonsets"},
+	{kind: KubernetesWorkloadKindRollout, apiPath: "/apis/argoproj.io/v1alpha1", resource: "rollouts"},
+}
+
+// KubernetesWorkloadKinds returns all supported workload kinds
+func KubernetesWorkloadKinds() []string {
+	kinds := make([]string, len(kubernetesWorkloadResources))
+
+	for i, workloadResource := range kubernetesWorkloadResources {
+		kinds[i] = workloadResource.kind
+	}
+
+	return kinds
+}
+
+// ParseKubernetesWorkloadKind returns the workload kind matching the name case-insensitively, e.g. "statefulset"
+func ParseKubernetesWorkloadKind(name string) (string, error) {
+	for _, workloadResource := range kubernetesWorkloadResources {
+		if strings.EqualFold(workloadResource.kind, name) {
+			return workloadResource.kind, nil
+		}
+	}
+
+	return "", fmt.Errorf("'%s' is not a valid kubernetes workload kind, choose between %v", name, KubernetesWorkloadKinds())
+}
+
+type KubernetesWorkload struct {
+	Kind       string
+	Namespace  string
+	Name       string
+	Selector   *KubernetesLabelSelector
+	PodLabels  map[string]string
+	Containers []string
+}
+
+// KubernetesLabelSelector is the selector of the pods of a workload
+type KubernetesLabelSelector struct {
+	MatchLabels      map[string]string                    `json:"matchLabels"`
+	MatchExpressions []KubernetesLabelSelectorRequirement `json:"matchExpressions"`
+}
+
+type KubernetesLabelSelectorRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+type KubernetesService struct {
+	Namespace string
+	Name      string
+	Selector  map[string]string
+	Ports     []KubernetesServicePort
+}
+
+type KubernetesServicePort struct {
+	Name string
+	Port int
+}
+
+type kubernetesObjectMeta struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels"`
+}
+
+type kubernetesWorkloadObject struct {
+	Metadata kubernetesObjectMeta `json:"metadata"`
+	Spec     struct {
+		Selector *KubernetesLabelSelector `json:"selector"`
+		// WorkloadRef is used by rollouts which are referring to the pod template of an existing deployment
+		WorkloadRef *struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"workloadRef"`
+		Template struct {
+			Metadata kubernetesObjectMeta `json:"metadata"`
+			Spec     struct {
+				Containers []struct {
+					Name string `json:"name"`
+				} `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+type kubernetesServiceList struct {
+	Items []struct {
+		Metadata kubernetesObjectMeta `json:"metadata"`
+		Spec     struct {
+			Selector map[string]string `json:"selector"`
+			Ports    []struct {
+				Name string `json:"name"`
+				Port int    `json:"port"`
+			} `json:"ports"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+// KubernetesClient is a minimal client of the kubernetes api which only knows about the few resources needed to
+// discover the workload of an application. The services are cached per namespace, as many applications share one.
+type KubernetesClient struct {
+	client rest.Interface
+
+	lck      sync.Mutex
+	services map[string]*kubernetesServicesResult
+}
+
+type kubernetesServicesResult struct {
+	done     chan struct{}
+	services []KubernetesService
+	err      error
+}
+
+func NewKubernetesClient(settings KubernetesSettings) (*KubernetesClient, error) {
+	config, err := loadKubernetesRestConfig(settings)
+	if err != nil {
+		return nil, fmt.Errorf("can not load kubernetes configuration: %w", err)
+	}
+
+	// the responses are decoded into the few fields needed here, so the client doesn't need to know the api types
+	config.GroupVersion = &schema.GroupVersion{}
+	config.NegotiatedSerializer = serializer.NewCodecFactory(runtime.NewScheme()).WithoutConversion()
+	config.AcceptContentTypes = runtime.ContentTypeJSON
+
+	client, err := rest.UnversionedRESTClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("can not create kubernetes client: %w", err)
+	}
+
+	return &KubernetesClient{
+		client:   client,
+		services: make(map[string]*kubernetesServicesResult),
+	}, nil
+}
+
+// get decodes the object at the path of the api into the result, it returns false if there is no such object
+func (c *KubernetesClient) get(ctx context.Context, path string, result any) (bool, error) {
+	body, err := c.client.Get().AbsPath(path).Do(ctx).Raw()
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if err = json.Unmarshal(body, result); err != nil {
+		return false, fmt.Errorf("can not decode the response: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetWorkload looks up the workload with the given name. If no kinds are given, all supported kinds are tried in order.
+func (c *KubernetesClient) GetWorkload(ctx context.Context, namespace, name string, kinds ...string) (*KubernetesWorkload, error) {
+	if len(kinds) == 0 {
+		kinds = KubernetesWorkloadKinds()
+	}
+
+	for _, workloadResource := range kubernetesWorkloadResources {
+		if !funk.ContainsString(kinds, workloadResource.kind) {
+			continue
+		}
+
+		workload, err := c.getWorkload(ctx, workloadResource, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if workload != nil {
+			return workload, nil
+		}
+	}
+
+	return nil, fmt.Errorf("there was no kubernetes workload of kind %s named %s/%s found", strings.Join(kinds, ", "), namespace, name)
+}
+
+// getWorkload returns nil if there is no workload of the kind with the given name
+func (c *KubernetesClient) getWorkload(ctx context.Context, workloadResource kubernetesWorkloadResource, namespace, name string) (*KubernetesWorkload, error) {
+	object := &kubernetesWorkloadObject{}
+	path := fmt.Sprintf("%s/namespaces/%s/%s/%s", workloadResource.apiPath, namespace, workloadResource.resource, name)
+
+	found, err := c.get(ctx, path, object)
+	if err != nil {
+		return nil, fmt.Errorf("can not get kubernetes %s %s/%s: %w", workloadResource.resource, namespace, name, err)
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	workload := &KubernetesWorkload{
+		Kind:       workloadResource.kind,
+		Namespace:  namespace,
+		Name:       name,
+		Selector:   object.Spec.Selector,
+		PodLabels:  object.Spec.Template.Metadata.Labels,
+		Containers: make([]string, len(object.Spec.Template.Spec.Containers)),
+	}
+
+	for i, container := range object.Spec.Template.Spec.Containers {
+		workload.Containers[i] = container.Name
+	}
+
+	if ref := object.Spec.WorkloadRef; ref != nil && ref.Kind == KubernetesWorkloadKindDeployment && len(workload.Containers) == 0 {
+		referenced, err := c.GetWorkload(ctx, namespace, ref.Name, KubernetesWorkloadKindDeployment)
+		if err != nil {
+			return nil, fmt.Errorf("can not get the workload referenced by %s %s/%s: %w", workloadResource.resource, namespace, name, err)
+		}
+
+		workload.PodLabels = referenced.PodLabels
+		workload.Containers = referenced.Containers
+
+		if workload.Selector == nil {
+			workload.Selector = referenced.Selector
+		}
+	}
+
+	return workload, nil
+}
+
+// GetServices returns the cached services of the namespace, failed lists aren't cached
+func (c *KubernetesClient) GetServices(ctx context.Context, namespace string) ([]KubernetesService, error) {
+	c.lck.Lock()
+	result, ok := c.services[namespace]
+	if !ok {
+		result = &kubernetesServicesResult{
+			done: make(chan struct{}),
+		}
+		c.services[namespace] = result
+	}
+	c.lck.Unlock()
+
+	if !ok {
+		result.services, result.err = c.listServices(ctx, namespace)
+
+		if result.err != nil {
+			c.lck.Lock()
+			delete(c.services, namespace)
+			c.lck.Unlock()
+		}
+
+		close(result.done)
+	}
+
+	select {
+	case <-result.done:
+		return result.services, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// GetWorkloadServices returns the services of the namespace which are selecting the pods of the workload
+func (c *KubernetesClient) GetWorkloadServices(ctx context.Context, workload *KubernetesWorkload) ([]KubernetesService, error) {
+	services, err := c.GetServices(ctx, workload.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]KubernetesService, 0)
+
+	for _, service := range services {
+		if len(service.Selector) > 0 && isLabelSubset(service.Selector, workload.PodLabels) {
+			selected = append(selected, service)
+		}
+	}
+
+	return selected, nil
+}
+
+func (c *KubernetesClient) listServices(ctx context.Context, namespace string) ([]KubernetesService, error) {
+	list := &kubernetesServiceList{}
+
+	found, err := c.get(ctx, fmt.Sprintf("/api/v1/namespaces/%s/services", namespace), list)
+	if err != nil {
+		return nil, fmt.Errorf("can not list kubernetes services of namespace %s: %w", namespace, err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("can not list kubernetes services of namespace %s: the namespace was not found", namespace)
+	}
+
+	services := make([]KubernetesService, len(list.Items))
+
+	for i, item := range list.Items {
+		services[i] = KubernetesService{
+			Namespace: namespace,
+			Name:      item.Metadata.Name,
+			Selector:  item.Spec.Selector,
+			Ports:     make([]KubernetesServicePort, len(item.Spec.Ports)),
+		}
+
+		for j, port := range item.Spec.Ports {
+			services[i].Ports[j] = KubernetesServicePort{
+				Name: port.Name,
+				Port: port.Port,
+			}
+		}
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+
+	return services, nil
+}
+
+// ContainerNames returns the containers of the pod template except for the ones matching one of the exclude expressions
+func (w *KubernetesWorkload) ContainerNames(excludes []*regexp.Regexp) []string {
+	containers := make([]string, 0, len(w.Containers))
+
+	for _, container := range w.Containers {
+		if matchesAny(container, excludes) {
+			continue
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers
+}
+
+func isLabelSubset(subset map[string]string, labels map[string]string) bool {
+	for key, value := range subset {
+		if labels[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PrometheusMatchers returns the label matchers selecting the pods in the kube_pod_labels metric of kube-state-metrics,
+// which exposes the pod labels prefixed with "label_". It is false if the selector can't be expressed by the matchers.
+func (s *KubernetesLabelSelector) PrometheusMatchers() ([]string, bool) {
+	if s == nil || (len(s.MatchLabels) == 0 && len(s.MatchExpressions) == 0) {
+		return nil, false
+	}
+
+	matchers := make([]string, 0, len(s.MatchLabels)+len(s.MatchExpressions))
+
+	for _, key := range sortedKeys(s.MatchLabels) {
+		matchers = append(matchers, fmt.Sprintf(`%s=%q`, getKubePodLabelName(key), s.MatchLabels[key]))
+	}
+
+	for _, requirement := range s.MatchExpressions {
+		label := getKubePodLabelName(requirement.Key)
+		values := make([]string, len(requirement.Values))
+
+		for i, value := range requirement.Values {
+			values[i] = regexp.QuoteMeta(value)
+		}
+
+		switch requirement.Operator {
+		case "In":
+			matchers = append(matchers, fmt.Sprintf(`%s=~%q`, label, strings.Join(values, "|")))
+		case "NotIn":
+			matchers = append(matchers, fmt.Sprintf(`%s!~%q`, label, strings.Join(values, "|")))
+		case "Exists":
+			matchers = append(matchers, fmt.Sprintf(`%s!=""`, label))
+		case "DoesNotExist":
+			matchers = append(matchers, fmt.Sprintf(`%s=""`, label))
+		default:
+			return nil, false
+		}
+	}
+
+	return matchers, true
+}
+
+var kubePodLabelNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// getKubePodLabelName returns the name kube-state-metrics uses for the pod label, e.g. label_app_kubernetes_io_name
+func getKubePodLabelName(key string) string {
+	return "label_" + kubePodLabelNameInvalidChars.ReplaceAllString(key, "_")
+}
@@ -0,0 +1,80 @@
+package builder
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type KubernetesSettings struct {
+	ConfigPath           string
+	ConfigContext        string
+	InCluster            bool
+	Host                 string
+	Token                string
+	ClusterCaCertificate string
+	Insecure             bool
+}
+
+// loadKubernetesRestConfig loads the configuration with the loading rules of kubectl: the given kubeconfig, otherwise
+// the ones of KUBECONFIG or ~/.kube/config, falling back to the in-cluster configuration. The credential plugins of
+// the kubeconfig are run by client-go. The explicit settings override the values of the kubeconfig.
+func loadKubernetesRestConfig(settings KubernetesSettings) (*rest.Config, error) {
+	var err error
+	var config *rest.Config
+
+	if settings.InCluster {
+		if config, err = rest.InClusterConfig(); err != nil {
+			return nil, fmt.Errorf("can not load in-cluster configuration: %w", err)
+		}
+
+		return overrideKubernetesRestConfig(config, settings), nil
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = settings.ConfigPath
+
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: settings.ConfigContext,
+	}
+
+	config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+
+	switch {
+	case err == nil:
+		return overrideKubernetesRestConfig(config, settings), nil
+	case clientcmd.IsEmptyConfig(err) && settings.Host != "":
+		// without any kubeconfig the api server can still be configured completely by the settings
+		return overrideKubernetesRestConfig(&rest.Config{}, settings), nil
+	default:
+		return nil, err
+	}
+}
+
+func overrideKubernetesRestConfig(config *rest.Config, settings KubernetesSettings) *rest.Config {
+	if settings.Host != "" {
+		config.Host = settings.Host
+	}
+
+	if settings.Token != "" {
+		config.BearerToken = settings.Token
+		config.BearerTokenFile = ""
+		config.ExecProvider = nil
+		config.AuthProvider = nil
+	}
+
+	if settings.ClusterCaCertificate != "" {
+		config.CAData = []byte(settings.ClusterCaCertificate)
+		config.CAFile = ""
+	}
+
+	if settings.Insecure {
+		// client-go refuses a ca certificate next to the insecure flag
+		config.Insecure = true
+		config.CAData = nil
+		config.CAFile = ""
+	}
+
+	return config
+}
@@ -0,0 +1,433 @@
+package builder_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+const kubernetesDeploymentResponse = `{
+	"metadata": {"name": "grp-app", "namespace": "prj"},
+	"spec": {
+		"selector": {"matchLabels": {"app": "grp-app"}},
+		"template": {
+			"metadata": {"labels": {"app": "grp-app", "team": "grp"}},
+			"spec": {"containers": [{"name": "app"}, {"name": "log_router"}]}
+		}
+	}
+}`
+
+const kubernetesServicesResponse = `{
+	"items": [
+		{"metadata": {"name": "grp-app-metrics"}, "spec": {"selector": {"app": "grp-app"}, "ports": [{"name": "metrics", "port": 8092}]}},
+		{"metadata": {"name": "grp-app"}, "spec": {"selector": {"app": "grp-app"}, "ports": [{"name": "http", "port": 8080}]}},
+		{"metadata": {"name": "other"}, "spec": {"selector": {"app": "other"}, "ports": [{"name": "http", "port": 8080}]}}
+	]
+}`
+
+func provideKubernetesApiServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]string) {
+	ts, requests := newKubernetesApiServer(t, responses)
+	ts.Start()
+
+	return ts, requests
+}
+
+// provideKubernetesApiServerTLS is needed for the kubeconfigs, client-go only sends the credentials of a kubeconfig via tls
+func provideKubernetesApiServerTLS(t *testing.T, responses map[string]string) (*httptest.Server, *[]string) {
+	ts, requests := newKubernetesApiServer(t, responses)
+	ts.StartTLS()
+
+	return ts, requests
+}
+
+func newKubernetesApiServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]string) {
+	// the kubeconfig of the machine running the tests must not be picked up
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+
+	requests := make([]string, 0)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s", request.Header.Get("Authorization"), request.URL.Path))
+
+		response, ok := responses[request.URL.Path]
+		if !ok {
+			writer.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		_, err := writer.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, &requests
+}
+
+func TestKubernetesDiscoverResources(t *testing.T) {
+	ts, requests := provideKubernetesApiServer(t, map[string]string{
+		"/apis/apps/v1/namespaces/prj/deployments/grp-app": kubernetesDeploymentResponse,
+		"/api/v1/namespaces/prj/services":                  kubernetesServicesResponse,
+	})
+
+	client, err := builder.NewKubernetesClient(builder.KubernetesSettings{
+		Host:  ts.URL,
+		Token: "secret",
+	})
+	assert.NoError(t, err)
+
+	orchestrator, ok := builder.GetOrchestrator("kubernetes")
+	assert.True(t, ok)
+
+	settings := builder.OrchestratorSettings{
+		KubernetesClient: client,
+		NamePatterns: builder.OrchestratorNamePatterns{
+			KubernetesNamespace: "{project}",
+			KubernetesPod:       "{group}-{app}",
+			TraefikServiceName:  "{project}-{group}-{app}-8080@kubernetes",
+		},
+		ExcludeContainers: []string{"^log_router$"},
+	}
+
+	resourceNames := &builder.ResourceNames{}
+	err = orchestrator.DiscoverResources(context.Background(), settings, provideAppId(), resourceNames)
+	assert.NoError(t, err)
+
+	assert.Equal(t, builder.KubernetesWorkloadKindDeployment, resourceNames.KubernetesWorkloadKind)
+	assert.Equal(t, []string{"app"}, resourceNames.Containers)
+	assert.Equal(t, "prj-grp-app-8080@kubernetes", resourceNames.TraefikServiceName)
+	assert.Equal(t, []string{
+		"Bearer secret /apis/apps/v1/namespaces/prj/deployments/grp-app",
+		"Bearer secret /api/v1/namespaces/prj/services",
+	}, *requests)
+
+	// the services of the namespace are only listed once
+	resourceNames = &builder.ResourceNames{Containers: []string{"app"}}
+	settings.NamePatterns.TraefikServiceName = "unknown"
+	err = orchestrator.DiscoverResources(context.Background(), settings, provideAppId(), resourceNames)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "prj-grp-app-8080@kubernetes", resourceNames.TraefikServiceName)
+	assert.Len(t, *requests, 3)
+}
+
+func TestKubernetesDiscoverResourcesStatefulSet(t *testing.T) {
+	ts, _ := provideKubernetesApiServer(t, map[string]string{
+		"/apis/apps/v1/namespaces/prj/statefulsets/grp-app": kubernetesDeploymentResponse,
+		"/api/v1/namespaces/prj/services":                   `{"items": []}`,
+	})
+
+	client, err := builder.NewKubernetesClient(builder.KubernetesSettings{
+		Host: ts.URL,
+	})
+	assert.NoError(t, err)
+
+	orchestrator, _ := builder.GetOrchestrator("kubernetes")
+	settings := builder.OrchestratorSettings{
+		KubernetesClient: client,
+		NamePatterns: builder.OrchestratorNamePatterns{
+			KubernetesNamespace: "{project}",
+			KubernetesPod:       "{group}-{app}",
+			TraefikServiceName:  "{project}-{group}-{app}-8080@kubernetes",
+		},
+	}
+
+	resourceNames := &builder.ResourceNames{}
+	err = orchestrator.DiscoverResources(context.Background(), settings, provideAppId(), resourceNames)
+	assert.NoError(t, err)
+
+	assert.Equal(t, builder.KubernetesWorkloadKindStatefulSet, resourceNames.KubernetesWorkloadKind)
+	assert.Equal(t, []string{"app", "log_router"}, resourceNames.Containers)
+	// the pods are matched by their name unless selecting them by their labels is enabled
+	assert.Empty(t, resourceNames.KubernetesPodSelector)
+	assert.Equal(t, `namespace="prj", pod=~"^grp-app-[0-9]+$"`, orchestrator.PodLabelFilter(resourceNames))
+	assert.Equal(t, `sum(kube_statefulset_status_replicas_ready{namespace="prj", statefulset="grp-app"})`, orchestrator.ReplicaQuery(resourceNames))
+
+	settings.KubernetesSelectPodsByLabels = true
+	resourceNames = &builder.ResourceNames{}
+	err = orchestrator.DiscoverResources(context.Background(), settings, provideAppId(), resourceNames)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{`label_app="grp-app"`}, resourceNames.KubernetesPodSelector)
+	assert.Equal(t, `namespace="prj"`, orchestrator.PodLabelFilter(resourceNames))
+}
+
+func TestKubernetesLabelSelectorPrometheusMatchers(t *testing.T) {
+	selector := &builder.KubernetesLabelSelector{
+		MatchLabels: map[string]string{
+			"app.kubernetes.io/name":     "grp-app",
+			"app.kubernetes.io/instance": "prj",
+		},
+		MatchExpressions: []builder.KubernetesLabelSelectorRequirement{
+			{Key: "track", Operator: "In", Values: []string{"stable", "canary.v2"}},
+			{Key: "tier", Operator: "NotIn", Values: []string{"cache"}},
+			{Key: "team", Operator: "Exists"},
+			{Key: "legacy", Operator: "DoesNotExist"},
+		},
+	}
+
+	matchers, ok := selector.PrometheusMatchers()
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`label_app_kubernetes_io_instance="prj"`,
+		`label_app_kubernetes_io_name="grp-app"`,
+		`label_track=~"stable|canary\\.v2"`,
+		`label_tier!~"cache"`,
+		`label_team!=""`,
+		`label_legacy=""`,
+	}, matchers)
+
+	_, ok = (&builder.KubernetesLabelSelector{}).PrometheusMatchers()
+	assert.False(t, ok)
+
+	_, ok = (*builder.KubernetesLabelSelector)(nil).PrometheusMatchers()
+	assert.False(t, ok)
+}
+
+func TestKubernetesPodSelectorQueries(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		KubernetesNamespace:   "prj",
+		KubernetesDeployment:  "grp-app",
+		KubernetesPod:         "grp-app",
+		KubernetesPodSelector: []string{`label_app="grp-app"`, `label_track=~"stable"`},
+		Containers:            []string{"app"},
+	}
+
+	db := newDashboardBuilder(t, resourceNames, "kubernetes")
+	db.AddPanel(builder.NewPanelKubernetesHealthyPods)
+	dashboard := db.Build("")
+
+	expected := `count(kube_pod_status_ready{condition="true",namespace="prj"} * on(namespace, pod) group_left() max by (namespace, pod) (kube_pod_labels{namespace="prj", label_app="grp-app", label_track=~"stable"}))`
+	assert.Equal(t, expected, dashboard.Panels[0].Targets[0].(builder.PanelTargetPrometheus).Expression)
+
+	orchestrator, _ := builder.GetOrchestrator("kubernetes")
+	queries := orchestrator.ContainerCpuQueries(resourceNames, 0)
+	assert.Equal(t, `max(kube_pod_container_resource_requests{resource="cpu",namespace="prj", container=~"app"} * on(namespace, pod) group_left() max by (namespace, pod) (kube_pod_labels{namespace="prj", label_app="grp-app", label_track=~"stable"}))`, queries.Requests)
+}
+
+func TestKubernetesDiscoverResourcesMissingWorkload(t *testing.T) {
+	ts, _ := provideKubernetesApiServer(t, map[string]string{})
+
+	client, err := builder.NewKubernetesClient(builder.KubernetesSettings{
+		Host: ts.URL,
+	})
+	assert.NoError(t, err)
+
+	orchestrator, _ := builder.GetOrchestrator("kubernetes")
+	settings := builder.OrchestratorSettings{
+		KubernetesClient: client,
+		NamePatterns: builder.OrchestratorNamePatterns{
+			KubernetesNamespace: "prj",
+			KubernetesPod:       "grp-app",
+		},
+	}
+
+	err = orchestrator.DiscoverResources(context.Background(), settings, provideAppId(), &builder.ResourceNames{})
+	assert.EqualError(t, err, "can not get kubernetes workload: there was no kubernetes workload of kind Deployment, StatefulSet, DaemonSet, Rollout named prj/grp-app found")
+}
+
+func TestKubernetesDiscoverResourcesExplicitWorkloadKind(t *testing.T) {
+	ts, requests := provideKubernetesApiServer(t, map[string]string{
+		"/apis/argoproj.io/v1alpha1/namespaces/prj/rollouts/grp-app": `{"spec": {"workloadRef": {"kind": "Deployment", "name": "grp-app-template"}}}`,
+		"/apis/apps/v1/namespaces/prj/deployments/grp-app-template":  kubernetesDeploymentResponse,
+		"/api/v1/namespaces/prj/services":                            kubernetesServicesResponse,
+	})
+
+	client, err := builder.NewKubernetesClient(builder.KubernetesSettings{
+		Host: ts.URL,
+	})
+	assert.NoError(t, err)
+
+	orchestrator, _ := builder.GetOrchestrator("kubernetes")
+	settings := builder.OrchestratorSettings{
+		KubernetesClient:       client,
+		KubernetesWorkloadKind: builder.KubernetesWorkloadKindRollout,
+		NamePatterns: builder.OrchestratorNamePatterns{
+			KubernetesNamespace: "{project}",
+			KubernetesPod:       "{group}-{app}",
+		},
+	}
+
+	resourceNames := &builder.ResourceNames{}
+	err = orchestrator.DiscoverResources(context.Background(), settings, provideAppId(), resourceNames)
+	assert.NoError(t, err)
+
+	assert.Equal(t, builder.KubernetesWorkloadKindRollout, resourceNames.KubernetesWorkloadKind)
+	assert.Equal(t, []string{"app", "log_router"}, resourceNames.Containers)
+	assert.Equal(t, "prj-grp-app-8080@kubernetes", resourceNames.TraefikServiceName)
+	assert.Equal(t, []string{
+		" /apis/argoproj.io/v1alpha1/namespaces/prj/rollouts/grp-app",
+		" /apis/apps/v1/namespaces/prj/deployments/grp-app-template",
+		" /api/v1/namespaces/prj/services",
+	}, *requests)
+}
+
+func TestKubernetesWorkloadKindQueries(t *testing.T) {
+	tests := []struct {
+		kind         string
+		podFilter    string
+		replicaQuery string
+	}{
+		{
+			kind:         "",
+			podFilter:    `namespace="prj", pod=~"^grp-app-[0-9a-f]+-[0-9a-z]+$"`,
+			replicaQuery: `sum(kube_deployment_status_replicas_ready{namespace="prj", deployment="grp-app"})`,
+		},
+		{
+			kind:         builder.KubernetesWorkloadKindStatefulSet,
+			podFilter:    `namespace="prj", pod=~"^grp-app-[0-9]+$"`,
+			replicaQuery: `sum(kube_statefulset_status_replicas_ready{namespace="prj", statefulset="grp-app"})`,
+		},
+		{
+			kind:         builder.KubernetesWorkloadKindDaemonSet,
+			podFilter:    `namespace="prj", pod=~"^grp-app-[0-9a-z]{5}$"`,
+			replicaQuery: `sum(kube_daemonset_status_number_ready{namespace="prj", daemonset="grp-app"})`,
+		},
+		{
+			kind:         builder.KubernetesWorkloadKindRollout,
+			podFilter:    `namespace="prj", pod=~"^grp-app-[0-9a-f]+-[0-9a-z]+$"`,
+			replicaQuery: `sum(rollout_info_replicas_available{namespace="prj", name="grp-app"})`,
+		},
+	}
+
+	orchestrator, _ := builder.GetOrchestrator("kubernetes")
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			resourceNames := &builder.ResourceNames{
+				KubernetesNamespace:    "prj",
+				KubernetesDeployment:   "grp-app",
+				KubernetesPod:          "grp-app",
+				KubernetesWorkloadKind: tt.kind,
+				Containers:             []string{"app"},
+			}
+
+			assert.Equal(t, tt.podFilter, orchestrator.PodLabelFilter(resourceNames))
+			assert.Equal(t, tt.replicaQuery, orchestrator.ReplicaQuery(resourceNames))
+
+			db := newDashboardBuilder(t, resourceNames, "kubernetes")
+			db.AddPanel(builder.NewPanelKubernetesHealthyPods)
+			dashboard := db.Build("")
+
+			assert.Equal(t, fmt.Sprintf(`count(kube_pod_status_ready{condition="true",%s})`, tt.podFilter), dashboard.Panels[0].Targets[0].(builder.PanelTargetPrometheus).Expression)
+		})
+	}
+}
+
+func TestParseKubernetesWorkloadKind(t *testing.T) {
+	kind, err := builder.ParseKubernetesWorkloadKind("statefulset")
+	assert.NoError(t, err)
+	assert.Equal(t, builder.KubernetesWorkloadKindStatefulSet, kind)
+
+	_, err = builder.ParseKubernetesWorkloadKind("cronjob")
+	assert.EqualError(t, err, "'cronjob' is not a valid kubernetes workload kind, choose between [Deployment StatefulSet DaemonSet Rollout]")
+}
+
+func TestKubernetesClientFromKubeconfig(t *testing.T) {
+	ts, requests := provideKubernetesApiServerTLS(t, map[string]string{
+		"/api/v1/namespaces/prj/services": `{"items": []}`,
+	})
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "token"), []byte("from-file"), 0o600)
+	assert.NoError(t, err)
+
+	configPath := writeKubeconfig(t, dir, ts, map[string]any{"tokenFile": "token"})
+
+	client, err := builder.NewKubernetesClient(builder.KubernetesSettings{
+		ConfigPath:    configPath,
+		ConfigContext: "local",
+	})
+	assert.NoError(t, err)
+
+	services, err := client.GetServices(context.Background(), "prj")
+	assert.NoError(t, err)
+	assert.Empty(t, services)
+	assert.Equal(t, []string{"Bearer from-file /api/v1/namespaces/prj/services"}, *requests)
+
+	_, err = builder.NewKubernetesClient(builder.KubernetesSettings{
+		ConfigPath:    configPath,
+		ConfigContext: "missing",
+	})
+	assert.EqualError(t, err, `can not load kubernetes configuration: context "missing" does not exist`)
+}
+
+func TestKubernetesClientFromDefaultKubeconfig(t *testing.T) {
+	ts, requests := provideKubernetesApiServerTLS(t, map[string]string{
+		"/api/v1/namespaces/prj/services": `{"items": []}`,
+	})
+
+	// the credential plugin only prints a token if it is called like kubectl would do it
+	dir := t.TempDir()
+	plugin := filepath.Join(dir, "credential-plugin")
+	script := `#!/bin/sh
+case "$KUBERNETES_EXEC_INFO" in
+  *client.authentication.k8s.io/v1*) ;;
+  *) echo "KUBERNETES_EXEC_INFO is missing" >&2; exit 1 ;;
+esac
+echo '{"apiVersion": "client.authentication.k8s.io/v1", "kind": "ExecCredential", "status": {"token": "from-plugin"}}'
+`
+	err := os.WriteFile(plugin, []byte(script), 0o700)
+	assert.NoError(t, err)
+
+	configPath := writeKubeconfig(t, dir, ts, map[string]any{
+		"exec": map[string]any{
+			"apiVersion":      "client.authentication.k8s.io/v1",
+			"command":         plugin,
+			"interactiveMode": "Never",
+		},
+	})
+	t.Setenv("KUBECONFIG", configPath)
+
+	client, err := builder.NewKubernetesClient(builder.KubernetesSettings{
+		ConfigContext: "local",
+	})
+	assert.NoError(t, err)
+
+	_, err = client.GetServices(context.Background(), "prj")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bearer from-plugin /api/v1/namespaces/prj/services"}, *requests)
+}
+
+// writeKubeconfig writes a kubeconfig with a "local" context for the given server and user and an unreachable current context
+func writeKubeconfig(t *testing.T, dir string, ts *httptest.Server, user map[string]any) string {
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+
+	kubeconfig := map[string]any{
+		"apiVersion":      "v1",
+		"kind":            "Config",
+		"current-context": "other",
+		"clusters": []any{
+			map[string]any{"name": "local", "cluster": map[string]any{"server": ts.URL, "certificate-authority-data": base64.StdEncoding.EncodeToString(caData)}},
+			map[string]any{"name": "remote", "cluster": map[string]any{"server": "https://example.invalid"}},
+		},
+		"contexts": []any{
+			map[string]any{"name": "local", "context": map[string]any{"cluster": "local", "user": "local"}},
+			map[string]any{"name": "other", "context": map[string]any{"cluster": "remote", "user": "local"}},
+		},
+		"users": []any{
+			map[string]any{"name": "local", "user": user},
+		},
+	}
+
+	// json is valid yaml, so there is no need for an additional encoder here
+	body, err := json.Marshal(kubeconfig)
+	assert.NoError(t, err)
+
+	configPath := filepath.Join(dir, "config")
+	err = os.WriteFile(configPath, body, 0o600)
+	assert.NoError(t, err)
+
+	return configPath
+}
@@ -0,0 +1,200 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/thoas/go-funk"
+)
+
+// dashboardLayout places panels on the grafana grid in the order they are added. It keeps track of the bottom of every
+// grid column, so panels of any width and height are packed into the highest free slot below the panels above them.
+// As no panel can move up anymore, grafana doesn't have to re-flow the dashboard when it is loaded.
+type dashboardLayout struct {
+	columns [DashboadWidth]int
+}
+
+func newDashboardLayout() *dashboardLayout {
+	return &dashboardLayout{}
+}
+
+// newDashboardLayoutBelow creates a layout for the panels of a collapsed row, which are placed below the row
+func newDashboardLayoutBelow(gridPos PanelGridPos) *dashboardLayout {
+	l := newDashboardLayout()
+
+	for i := range l.columns {
+		l.columns[i] = gridPos.Y + gridPos.H
+	}
+
+	return l
+}
+
+// cursor returns the position at which the next panel of the given size would be placed
+func (l *dashboardLayout) cursor(w int, h int) PanelGridPos {
+	x, y := l.fit(w)
+
+	return NewPanelGridPos(h, w, x, y)
+}
+
+// place moves the grid position of the panel to the next free slot and marks the covered columns as occupied
+func (l *dashboardLayout) place(panel Panel) Panel {
+	gridPos := panel.GridPos
+
+	if gridPos.W <= 0 || gridPos.W > DashboadWidth {
+		gridPos.W = DashboadWidth
+	}
+
+	if gridPos.H <= 0 {
+		gridPos.H = PanelHeight
+	}
+
+	if panel.Type == "row" {
+		// a row always spans the full width and starts below everything placed so far
+		gridPos.W = DashboadWidth
+	}
+
+	gridPos.X, gridPos.Y = l.fit(gridPos.W)
+
+	for i := gridPos.X; i < gridPos.X+gridPos.W; i++ {
+		l.columns[i] = gridPos.Y + gridPos.H
+	}
+
+	panel.GridPos = gridPos
+
+	return panel
+}
+
+// top returns the lowest occupied position of the given columns, which is the highest y a panel can be placed at
+func (l *dashboardLayout) top(x int, w int) int {
+	y := 0
+
+	for i := x; i < x+w; i++ {
+		y = max(y, l.columns[i])
+	}
+
+	return y
+}
+
+// fit returns the left most of the highest positions a panel of the given width can be placed at
+func (l *dashboardLayout) fit(w int) (int, int) {
+	bestX, bestY := 0, l.top(0, w)
+
+	for x := 1; x+w <= DashboadWidth; x++ {
+		if y := l.top(x, w); y < bestY {
+			bestX, bestY = x, y
+		}
+	}
+
+	return bestX, bestY
+}
+
+// PanelSizeSettings configure the size of panels, 0 keeps the size of the next broader setting. PanelsPerRow takes
+// precedence over Width and divides the dashboard width evenly between the panels of a row.
+type PanelSizeSettings struct {
+	Width        int
+	Height       int
+	PanelsPerRow int
+}
+
+type DashboardLayoutSettings struct {
+	// Panels is the default size of the panels of all sections
+	Panels PanelSizeSettings
+	// Sections override the size of the panels of single sections, like kinesis or logs
+	Sections map[string]PanelSizeSettings
+}
+
+// PanelLayout resolves the size of the panels per section. The zero value and nil keep the default sizes.
+type PanelLayout struct {
+	panels   PanelSizeSettings
+	sections map[string]PanelSizeSettings
+}
+
+func NewPanelLayout(settings DashboardLayoutSettings) (*PanelLayout, error) {
+	if err := validatePanelSize("panels", settings.Panels); err != nil {
+		return nil, err
+	}
+
+	for section, size := range settings.Sections {
+		if !funk.ContainsString(dashboardSections, section) {
+			return nil, fmt.Errorf("'%s' is not a valid section, choose between %v", section, dashboardSections)
+		}
+
+		if err := validatePanelSize(section, size); err != nil {
+			return nil, err
+		}
+	}
+
+	return &PanelLayout{
+		panels:   settings.Panels,
+		sections: settings.Sections,
+	}, nil
+}
+
+func validatePanelSize(name string, size PanelSizeSettings) error {
+	if size.Width < 0 || size.Width > DashboadWidth {
+		return fmt.Errorf("the panel width of %s has to be between 1 and %d, got %d", name, DashboadWidth, size.Width)
+	}
+
+	if size.PanelsPerRow < 0 || size.PanelsPerRow > DashboadWidth {
+		return fmt.Errorf("the panels per row of %s have to be between 1 and %d, got %d", name, DashboadWidth, size.PanelsPerRow)
+	}
+
+	if size.Height < 0 {
+		return fmt.Errorf("the panel height of %s can not be negative, got %d", name, size.Height)
+	}
+
+	return nil
+}
+
+// width returns the configured width of the panels, if there is any
+func (s PanelSizeSettings) width() (int, bool) {
+	switch {
+	case s.PanelsPerRow > 0:
+		return DashboadWidth / s.PanelsPerRow, true
+	case s.Width > 0:
+		return s.Width, true
+	default:
+		return 0, false
+	}
+}
+
+// defaultSize returns the size of the panels which don't belong to a section with its own size
+func (l *PanelLayout) defaultSize() (int, int) {
+	w, h := PanelWidth, PanelHeight
+
+	if l == nil {
+		return w, h
+	}
+
+	if width, ok := l.panels.width(); ok {
+		w = width
+	}
+
+	if l.panels.Height > 0 {
+		h = l.panels.Height
+	}
+
+	return w, h
+}
+
+// resize applies the size configured for the section to the panel. Panels of sections without a size of their own
+// keep the size they were built with.
+func (l *PanelLayout) resize(section string, panel Panel) Panel {
+	if l == nil || panel.Type == "row" {
+		return panel
+	}
+
+	size, ok := l.sections[section]
+	if !ok {
+		return panel
+	}
+
+	if width, ok := size.width(); ok {
+		panel.GridPos.W = width
+	}
+
+	if size.Height > 0 {
+		panel.GridPos.H = size.Height
+	}
+
+	return panel
+}
@@ -0,0 +1,204 @@
+package builder_test
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+type layoutPanel struct {
+	Title     string               `json:"title"`
+	Type      string               `json:"type"`
+	GridPos   builder.PanelGridPos `json:"gridPos"`
+	Collapsed bool                 `json:"collapsed,omitempty"`
+	Panels    []layoutPanel        `json:"panels,omitempty"`
+}
+
+// assertGolden compares the given value with the json stored in testdata/<name>.golden.json. Run the tests with
+// -update to rewrite the golden files after an intended change.
+func assertGolden(t *testing.T, name string, value any) {
+	actual, err := json.MarshalIndent(value, "", "  ")
+	assert.NoError(t, err)
+
+	actual = append(actual, '\n')
+	path := filepath.Join("testdata", name+".golden.json")
+
+	if *updateGolden {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	if !assert.NoError(t, err, "run the tests with -update to create the golden file") {
+		return
+	}
+
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func newSizedPanel(title string, w int, h int) builder.PanelFactory {
+	return func(_ builder.PanelSettings) builder.Panel {
+		return builder.Panel{
+			GridPos: builder.NewPanelGridPos(h, w, 0, 0),
+			Title:   title,
+			Type:    "timeseries",
+		}
+	}
+}
+
+func newDefaultPanel(title string) builder.PanelFactory {
+	return newSizedPanel(title, builder.PanelWidth, builder.PanelHeight)
+}
+
+func toLayoutPanels(dashboardPanels []builder.Panel) []layoutPanel {
+	panels := make([]layoutPanel, len(dashboardPanels))
+
+	for i, panel := range dashboardPanels {
+		panels[i] = layoutPanel{
+			Title:     panel.Title,
+			Type:      panel.Type,
+			GridPos:   panel.GridPos,
+			Collapsed: panel.Collapsed,
+			Panels:    toLayoutPanels(panel.Panels),
+		}
+	}
+
+	return panels
+}
+
+func provideLayoutResourceNames() *builder.ResourceNames {
+	return &builder.ResourceNames{
+		Environment:                        "test",
+		GrafanaCloudWatchDatasourceName:    "cw",
+		GrafanaElasticsearchDatasourceName: "elastic",
+		TargetGroups: []builder.ElbTargetGroup{
+			{LoadBalancer: "lb", TargetGroup: "tg"},
+		},
+		Containers: []string{"app", "log_router"},
+	}
+}
+
+func TestDashboardLayout(t *testing.T) {
+	resourceNames := provideLayoutResourceNames()
+
+	tests := map[string]func(db *builder.DashboardBuilder){
+		"ecs_sections": func(db *builder.DashboardBuilder) {
+			db.AddServiceAndTask()
+			db.AddElbTargetGroups()
+			db.AddCloudAwsSqsQueue(builder.MetadataCloudAwsSqsQueue{QueueNameFull: "queue"})
+			db.AddCloudAwsSnsTopic(builder.MetadataCloudAwsSnsTopic{TopicName: "topic"})
+		},
+		"odd_panel_count": func(db *builder.DashboardBuilder) {
+			db.AddPanel(builder.NewPanelRow("first"))
+			db.AddPanel(newDefaultPanel("a"))
+			db.AddPanel(newDefaultPanel("b"))
+			db.AddPanel(newDefaultPanel("c"))
+			db.AddPanel(builder.NewPanelRow("second"))
+			db.AddPanel(newDefaultPanel("d"))
+		},
+		"mixed_sizes": func(db *builder.DashboardBuilder) {
+			db.AddPanel(builder.NewPanelRow("mixed"))
+			db.AddPanel(newSizedPanel("tall", 12, 16))
+			db.AddPanel(newSizedPanel("short", 12, 8))
+			db.AddPanel(newSizedPanel("below short", 12, 8))
+			db.AddPanel(newSizedPanel("third", 8, 4))
+			db.AddPanel(newSizedPanel("third wide", 16, 6))
+			db.AddPanel(newSizedPanel("too wide", 30, 2))
+			db.AddPanel(builder.NewPanelLogs)
+			db.AddPanel(builder.NewPanelRow("after logs"))
+			db.AddPanel(newSizedPanel("quarter", 6, 8))
+		},
+	}
+
+	for name, addPanels := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := newDashboardBuilder(t, resourceNames, "ecs")
+			addPanels(db)
+			dashboard := db.Build("layout")
+
+			assertGolden(t, fmt.Sprintf("layout_%s", name), toLayoutPanels(dashboard.Panels))
+		})
+	}
+}
+
+func TestDashboardCollapsedSections(t *testing.T) {
+	tests := map[string][]string{
+		"sqs_and_http_routes": {builder.DashboardSectionSqs, builder.DashboardSectionHttpRoutes},
+		"all":                 {builder.DashboardSectionAll},
+	}
+
+	for name, sections := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := newDashboardBuilder(t, provideLayoutResourceNames(), "ecs", builder.WithCollapsedSections(sections...))
+			db.AddServiceAndTask()
+			db.AddHttpServerHandler("default", builder.MetadataHttpServerHandler{Method: "GET", Path: "/a"})
+			db.AddHttpServerHandler("default", builder.MetadataHttpServerHandler{Method: "GET", Path: "/b"})
+			db.AddCloudAwsSqsQueue(builder.MetadataCloudAwsSqsQueue{QueueNameFull: "queue"})
+			db.AddCloudAwsSnsTopic(builder.MetadataCloudAwsSnsTopic{TopicName: "topic"})
+			dashboard := db.Build("layout")
+
+			assertGolden(t, fmt.Sprintf("layout_collapsed_%s", name), toLayoutPanels(dashboard.Panels))
+
+			for _, panel := range dashboard.Panels {
+				if !panel.Collapsed {
+					continue
+				}
+
+				assert.Equal(t, "row", panel.Type)
+				assert.NotEmpty(t, panel.Panels, panel.Title)
+
+				for _, child := range panel.Panels {
+					assert.Greater(t, child.GridPos.Y, panel.GridPos.Y, child.Title)
+				}
+			}
+		})
+	}
+}
+
+func TestDashboardPanelLayout(t *testing.T) {
+	layout, err := builder.NewPanelLayout(builder.DashboardLayoutSettings{
+		Panels: builder.PanelSizeSettings{
+			Height: 6,
+		},
+		Sections: map[string]builder.PanelSizeSettings{
+			builder.DashboardSectionKinesis: {PanelsPerRow: 3},
+			builder.DashboardSectionLogs:    {Height: 24},
+			builder.DashboardSectionSqs:     {Width: 24, Height: 4},
+		},
+	})
+	assert.NoError(t, err)
+
+	db := newDashboardBuilder(t, provideLayoutResourceNames(), "ecs", builder.WithLayout(layout))
+	db.AddErrorsAndWarnings()
+	db.AddCloudAwsKinesisKinsumer(builder.MetadataCloudAwsKinesisKinsumer{StreamNameFull: "stream"})
+	db.AddCloudAwsSqsQueue(builder.MetadataCloudAwsSqsQueue{QueueNameFull: "queue"})
+	db.AddCloudAwsSnsTopic(builder.MetadataCloudAwsSnsTopic{TopicName: "topic"})
+	dashboard := db.Build("layout")
+
+	assertGolden(t, "layout_panel_sizes", toLayoutPanels(dashboard.Panels))
+}
+
+func TestNewPanelLayoutErrors(t *testing.T) {
+	_, err := builder.NewPanelLayout(builder.DashboardLayoutSettings{
+		Sections: map[string]builder.PanelSizeSettings{"fancy": {Width: 6}},
+	})
+	assert.ErrorContains(t, err, "'fancy' is not a valid section")
+
+	_, err = builder.NewPanelLayout(builder.DashboardLayoutSettings{
+		Panels: builder.PanelSizeSettings{Width: 30},
+	})
+	assert.EqualError(t, err, "the panel width of panels has to be between 1 and 24, got 30")
+
+	_, err = builder.NewPanelLayout(builder.DashboardLayoutSettings{
+		Sections: map[string]builder.PanelSizeSettings{builder.DashboardSectionSqs: {Height: -1}},
+	})
+	assert.EqualError(t, err, "the panel height of sqs can not be negative, got -1")
+}
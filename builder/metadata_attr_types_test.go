@@ -0,0 +1,116 @@
+package builder_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertAttrTypesInSync walks the go type along with the attr type and checks that every json field of the metadata
+// is exposed to terraform with the name of its json tag and a matching type
+func assertAttrTypesInSync(t *testing.T, path string, goType reflect.Type, attrType attr.Type) {
+	switch goType.Kind() {
+	case reflect.Struct:
+		objectType, ok := attrType.(types.ObjectType)
+		if !assert.True(t, ok, "%s: expected an object type, got %T", path, attrType) {
+			return
+		}
+
+		fieldNames := make([]string, 0, goType.NumField())
+
+		for i := 0; i < goType.NumField(); i++ {
+			name := strings.Split(goType.Field(i).Tag.Get("json"), ",")[0]
+			fieldNames = append(fieldNames, name)
+
+			fieldAttrType, ok := objectType.AttrTypes[name]
+			if !assert.True(t, ok, "%s: there is no attr type for the field %s", path, name) {
+				continue
+			}
+
+			assertAttrTypesInSync(t, path+"."+name, goType.Field(i).Type, fieldAttrType)
+		}
+
+		for name := range objectType.AttrTypes {
+			assert.Contains(t, fieldNames, name, "%s: there is no field for the attr type %s", path, name)
+		}
+	case reflect.Slice:
+		listType, ok := attrType.(types.ListType)
+		if !assert.True(t, ok, "%s: expected a list type, got %T", path, attrType) {
+			return
+		}
+
+		assertAttrTypesInSync(t, path+"[]", goType.Elem(), listType.ElemType)
+	case reflect.String:
+		assert.Equal(t, types.StringType, attrType, path)
+	case reflect.Bool:
+		assert.Equal(t, types.BoolType, attrType, path)
+	case reflect.Int, reflect.Int64:
+		assert.Equal(t, types.Int64Type, attrType, path)
+	default:
+		t.Errorf("%s: unsupported kind %s", path, goType.Kind())
+	}
+}
+
+func TestMetadataAttrTypesInSync(t *testing.T) {
+	attrType := types.ObjectType{
+		AttrTypes: builder.MetadataApplicationAttrTypes(),
+	}
+
+	assertAttrTypesInSync(t, "metadata", reflect.TypeOf(builder.MetadataApplication{}), attrType)
+}
+
+func TestMetadataToValue(t *testing.T) {
+	metadata := builder.MetadataApplication{
+		Cloud: builder.MetadataCloud{
+			Aws: builder.MetadataCloudAws{
+				Dynamodb: builder.MetadataCloudAwsDynamodb{
+					Tables: builder.MetadataCloudAwsDynamodbTables{
+						{TableName: "prj-env-fam-grp-app-table"},
+					},
+				},
+				Kinesis: builder.MetadataCloudAwsKinesis{
+					Kinsumers: builder.MetadataCloudAwsKinesisKinsumers{
+						{Name: "kinsumer", OpenShardCount: 2, StreamName: "stream"},
+					},
+				},
+				Sns: builder.MetadataCloudAwsSns{
+					Topics: builder.MetadataCloudAwsSnsTopics{
+						{TopicArn: "arn:aws:sns:eu-central-1:123456789012:prj-env-fam-grp-topic", TopicName: "prj-env-fam-grp-topic"},
+					},
+				},
+				Sqs: builder.MetadataCloudAwsSqs{
+					Queues: builder.MetadataCloudAwsSqsQueues{
+						{
+							QueueArn:      "arn:aws:sqs:eu-central-1:123456789012:prj-env-fam-grp-app-queue",
+							QueueName:     "queue",
+							QueueNameFull: "prj-env-fam-grp-app-queue",
+							QueueUrl:      "https://sqs.eu-central-1.amazonaws.com/123456789012/prj-env-fam-grp-app-queue",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	value := metadata.ToValue()
+
+	assert.True(t, value.Type(context.Background()).Equal(types.ObjectType{AttrTypes: builder.MetadataApplicationAttrTypes()}))
+
+	_, err := value.ToTerraformValue(context.Background())
+	assert.NoError(t, err)
+
+	aws := value.Attrs["cloud"].(types.Object).Attrs["aws"].(types.Object)
+	queue := aws.Attrs["sqs"].(types.Object).Attrs["queues"].(types.List).Elems[0].(types.Object)
+	topic := aws.Attrs["sns"].(types.Object).Attrs["topics"].(types.List).Elems[0].(types.Object)
+	table := aws.Attrs["dynamodb"].(types.Object).Attrs["tables"].(types.List).Elems[0].(types.Object)
+
+	assert.Equal(t, types.String{Value: "prj-env-fam-grp-app-queue"}, queue.Attrs["queue_name_full"])
+	assert.Equal(t, types.String{Value: "prj-env-fam-grp-topic"}, topic.Attrs["topic_name"])
+	assert.Equal(t, types.String{Value: "prj-env-fam-grp-app-table"}, table.Attrs["table_name"])
+}
@@ -36,7 +36,10 @@ type MetadataCloudAws struct {
 func (a MetadataCloudAws) ToValue() attr.Value {
 	return types.Object{
 		Attrs: map[string]attr.Value{
-			"kinesis": a.Kinesis.ToValue(),
+			"dynamodb": a.Dynamodb.ToValue(),
+			"kinesis":  a.Kinesis.ToValue(),
+			"sns":      a.Sns.ToValue(),
+			"sqs":      a.Sqs.ToValue(),
 		},
 		AttrTypes: MetadataCloudAwsAttrTypes(),
 	}
@@ -44,18 +47,78 @@ func (a MetadataCloudAws) ToValue() attr.Value {
 
 func MetadataCloudAwsAttrTypes() map[string]attr.Type {
 	return map[string]attr.Type{
+		"dynamodb": types.ObjectType{
+			AttrTypes: MetadataCloudAwsDynamodbAttrTypes(),
+		},
 		"kinesis": types.ObjectType{
 			AttrTypes: MetadataCloudAwsKinesisAttrTypes(),
 		},
+		"sns": types.ObjectType{
+			AttrTypes: MetadataCloudAwsSnsAttrTypes(),
+		},
+		"sqs": types.ObjectType{
+			AttrTypes: MetadataCloudAwsSqsAttrTypes(),
+		},
+	}
+}
+
+type MetadataCloudAwsDynamodb struct {
+	Tables MetadataCloudAwsDynamodbTables `json:"tables"`
+}
+
+func (d MetadataCloudAwsDynamodb) ToValue() attr.Value {
+	return types.Object{
+		Attrs: map[string]attr.Value{
+			"tables": d.Tables.ToValue(),
+		},
+		AttrTypes: MetadataCloudAwsDynamodbAttrTypes(),
+	}
+}
+
+func MetadataCloudAwsDynamodbAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"tables": types.ListType{
+			ElemType: types.ObjectType{
+				AttrTypes: MetadataCloudAwsDynamodbTableAttrTypes(),
+			},
+		},
+	}
+}
+
+type MetadataCloudAwsDynamodbTables []MetadataCloudAwsDynamodbTable
+
+func (d MetadataCloudAwsDynamodbTables) ToValue() types.List {
+	list := types.List{
+		Elems: make([]attr.Value, len(d)),
+		ElemType: types.ObjectType{
+			AttrTypes: MetadataCloudAwsDynamodbTableAttrTypes(),
+		},
+	}
+
+	for i, table := range d {
+		list.Elems[i] = table.ToValue()
 	}
+
+	return list
 }
 
 type MetadataCloudAwsDynamodbTable struct {
 	TableName string `json:"table_name"`
 }
 
-type MetadataCloudAwsDynamodb struct {
-	Tables []MetadataCloudAwsDynamodbTable `json:"tables"`
+func (t MetadataCloudAwsDynamodbTable) ToValue() types.Object {
+	return types.Object{
+		Attrs: map[string]attr.Value{
+			"table_name": types.String{Value: t.TableName},
+		},
+		AttrTypes: MetadataCloudAwsDynamodbTableAttrTypes(),
+	}
+}
+
+func MetadataCloudAwsDynamodbTableAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"table_name": types.StringType,
+	}
 }
 
 type MetadataCloudAwsKinesis struct {
@@ -207,13 +270,106 @@ func MetadataCloudAwsKinesisRecordWriterAttrTypes() map[string]attr.Type {
 	}
 }
 
+type MetadataCloudAwsSns struct {
+	Topics MetadataCloudAwsSnsTopics `json:"topics"`
+}
+
+func (s MetadataCloudAwsSns) ToValue() attr.Value {
+	return types.Object{
+		Attrs: map[string]attr.Value{
+			"topics": s.Topics.ToValue(),
+		},
+		AttrTypes: MetadataCloudAwsSnsAttrTypes(),
+	}
+}
+
+func MetadataCloudAwsSnsAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"topics": types.ListType{
+			ElemType: types.ObjectType{
+				AttrTypes: MetadataCloudAwsSnsTopicAttrTypes(),
+			},
+		},
+	}
+}
+
+type MetadataCloudAwsSnsTopics []MetadataCloudAwsSnsTopic
+
+func (s MetadataCloudAwsSnsTopics) ToValue() types.List {
+	list := types.List{
+		Elems: make([]attr.Value, len(s)),
+		ElemType: types.ObjectType{
+			AttrTypes: MetadataCloudAwsSnsTopicAttrTypes(),
+		},
+	}
+
+	for i, topic := range s {
+		list.Elems[i] = topic.ToValue()
+	}
+
+	return list
+}
+
 type MetadataCloudAwsSnsTopic struct {
 	TopicArn  string `json:"topic_arn"`
 	TopicName string `json:"topic_name"`
 }
 
-type MetadataCloudAwsSns struct {
-	Queues []MetadataCloudAwsSnsTopic `json:"topics"`
+func (t MetadataCloudAwsSnsTopic) ToValue() types.Object {
+	return types.Object{
+		Attrs: map[string]attr.Value{
+			"topic_arn":  types.String{Value: t.TopicArn},
+			"topic_name": types.String{Value: t.TopicName},
+		},
+		AttrTypes: MetadataCloudAwsSnsTopicAttrTypes(),
+	}
+}
+
+func MetadataCloudAwsSnsTopicAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"topic_arn":  types.StringType,
+		"topic_name": types.StringType,
+	}
+}
+
+type MetadataCloudAwsSqs struct {
+	Queues MetadataCloudAwsSqsQueues `json:"queues"`
+}
+
+func (s MetadataCloudAwsSqs) ToValue() attr.Value {
+	return types.Object{
+		Attrs: map[string]attr.Value{
+			"queues": s.Queues.ToValue(),
+		},
+		AttrTypes: MetadataCloudAwsSqsAttrTypes(),
+	}
+}
+
+func MetadataCloudAwsSqsAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"queues": types.ListType{
+			ElemType: types.ObjectType{
+				AttrTypes: MetadataCloudAwsSqsQueueAttrTypes(),
+			},
+		},
+	}
+}
+
+type MetadataCloudAwsSqsQueues []MetadataCloudAwsSqsQueue
+
+func (s MetadataCloudAwsSqsQueues) ToValue() types.List {
+	list := types.List{
+		Elems: make([]attr.Value, len(s)),
+		ElemType: types.ObjectType{
+			AttrTypes: MetadataCloudAwsSqsQueueAttrTypes(),
+		},
+	}
+
+	for i, queue := range s {
+		list.Elems[i] = queue.ToValue()
+	}
+
+	return list
 }
 
 type MetadataCloudAwsSqsQueue struct {
@@ -223,6 +379,23 @@ type MetadataCloudAwsSqsQueue struct {
 	QueueUrl      string `json:"queue_url"`
 }
 
-type MetadataCloudAwsSqs struct {
-	Queues []MetadataCloudAwsSqsQueue `json:"queues"`
+func (q MetadataCloudAwsSqsQueue) ToValue() types.Object {
+	return types.Object{
+		Attrs: map[string]attr.Value{
+			"queue_arn":       types.String{Value: q.QueueArn},
+			"queue_name":      types.String{Value: q.QueueName},
+			"queue_name_full": types.String{Value: q.QueueNameFull},
+			"queue_url":       types.String{Value: q.QueueUrl},
+		},
+		AttrTypes: MetadataCloudAwsSqsQueueAttrTypes(),
+	}
+}
+
+func MetadataCloudAwsSqsQueueAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"queue_arn":       types.StringType,
+		"queue_name":      types.StringType,
+		"queue_name_full": types.StringType,
+		"queue_url":       types.StringType,
+	}
 }
@@ -0,0 +1,140 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+const (
+	orchestratorEcs        = "ecs"
+	orchestratorEcsFargate = "ecs_fargate"
+	orchestratorKubernetes = "kubernetes"
+)
+
+// Orchestrator encapsulates everything which depends on where the application is running: how its containers and
+// pods are found in prometheus, how the resource usage is queried and how the orchestrator specific resources are discovered.
+type Orchestrator interface {
+	Name() string
+	// ContainerLabel is the prometheus label containing the name of a container
+	ContainerLabel() string
+	ContainerLabelFilter(resourceNames *ResourceNames, containerIndex int) string
+	PodLabelFilter(resourceNames *ResourceNames) string
+	ContainerCpuQueries(resourceNames *ResourceNames, containerIndex int) ResourceUsageQueries
+	ContainerMemoryQueries(resourceNames *ResourceNames, containerIndex int) ResourceUsageQueries
+	ServiceUtilizationQueries(resourceNames *ResourceNames) (cpuQuery string, memoryQuery string)
+	ReplicaQuery(resourceNames *ResourceNames) string
+	// ResourceUsagePanels are the panels rendered in the "Service Resource Usage" section
+	ResourceUsagePanels(resourceNames *ResourceNames) []PanelFactory
+	// ReplicaOverviewPanel shows the running replicas against the desired ones in the overview section
+	ReplicaOverviewPanel(resourceNames *ResourceNames) PanelFactory
+	// DeploymentAnnotation marks the deployments of the application, false if they can't be queried from prometheus
+	DeploymentAnnotation(resourceNames *ResourceNames) (DashboardAnnotation, bool)
+	DefaultTitle(resourceNames *ResourceNames) string
+	// UsesContainers is false if none of the panels query the containers, they aren't discovered then
+	UsesContainers() bool
+	// DiscoverResources fills the orchestrator specific fields of the resource names
+	DiscoverResources(ctx context.Context, settings OrchestratorSettings, appId AppId, resourceNames *ResourceNames) error
+}
+
+type ResourceUsageQueries struct {
+	Requests string
+	Limits   string
+	Minimum  string
+	Average  string
+	Maximum  string
+}
+
+type OrchestratorSettings struct {
+	AwsClients *AwsClients
+	// KubernetesClient is only set if the provider has a kubernetes configuration, otherwise everything is derived from the name patterns
+	KubernetesClient *KubernetesClient
+	// KubernetesWorkloadKind is the kind of the kubernetes workload, if empty it is discovered or a deployment is assumed
+	KubernetesWorkloadKind string
+	// KubernetesSelectPodsByLabels selects the pods by the selector of the discovered workload through kube_pod_labels
+	// instead of their name, which requires kube-state-metrics to expose the labels of the selector
+	KubernetesSelectPodsByLabels bool
+	NamePatterns                 OrchestratorNamePatterns
+	// ExcludeContainers are regular expressions for container names to skip when the containers are discovered
+	ExcludeContainers []string
+}
+
+type OrchestratorNamePatterns struct {
+	EcsCluster          string
+	EcsService          string
+	KubernetesNamespace string
+	KubernetesPod       string
+	TraefikServiceName  string
+}
+
+func newPrometheusResourceUsagePanels(resourceNames *ResourceNames) []PanelFactory {
+	panels := []PanelFactory{
+		NewPanelServiceUtilization,
+		NewPanelTaskDeployment,
+	}
+
+	for i := range resourceNames.Containers {
+		panels = append(panels, NewPanelContainerCpuFactory(i), NewPanelContainerMemoryFactory(i))
+	}
+
+	return panels
+}
+
+func compileExcludeContainers(patterns []string) ([]*regexp.Regexp, error) {
+	expressions := make([]*regexp.Regexp, len(patterns))
+
+	for i, pattern := range patterns {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid container exclude pattern %q: %w", pattern, err)
+		}
+
+		expressions[i] = expression
+	}
+
+	return expressions, nil
+}
+
+var (
+	orchestratorsLck = sync.RWMutex{}
+	orchestrators    = map[string]Orchestrator{}
+)
+
+func init() {
+	RegisterOrchestrator(ecsOrchestrator{})
+	RegisterOrchestrator(ecsFargateOrchestrator{})
+	RegisterOrchestrator(kubernetesOrchestrator{})
+}
+
+// RegisterOrchestrator makes an orchestrator available by its name, an already registered orchestrator with the same name is replaced
+func RegisterOrchestrator(orchestrator Orchestrator) {
+	orchestratorsLck.Lock()
+	defer orchestratorsLck.Unlock()
+
+	orchestrators[orchestrator.Name()] = orchestrator
+}
+
+func GetOrchestrator(name string) (Orchestrator, bool) {
+	orchestratorsLck.RLock()
+	defer orchestratorsLck.RUnlock()
+
+	orchestrator, ok := orchestrators[name]
+
+	return orchestrator, ok
+}
+
+func AvailableOrchestrators() []string {
+	orchestratorsLck.RLock()
+	defer orchestratorsLck.RUnlock()
+
+	names := make([]string, 0, len(orchestrators))
+	for name := range orchestrators {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,144 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+)
+
+type ecsOrchestrator struct{}
+
+func getEcsContainerLabelFilter(ecsClusterName, ecsTaskDefinitionName, containerName string) string {
+	return fmt.Sprintf(`container_label_com_amazonaws_ecs_cluster=%q, container_label_com_amazonaws_ecs_task_definition_family=%q, container_label_com_amazonaws_ecs_container_name=%q`, ecsClusterName, ecsTaskDefinitionName, containerName)
+}
+
+func getEcsTaskDefinitionLabelFilter(ecsClusterName, ecsTaskDefinitionName string) string {
+	return fmt.Sprintf(`container_label_com_amazonaws_ecs_cluster=%q, container_label_com_amazonaws_ecs_task_definition_family=%q`, ecsClusterName, ecsTaskDefinitionName)
+}
+
+func (o ecsOrchestrator) Name() string {
+	return orchestratorEcs
+}
+
+func (o ecsOrchestrator) ContainerLabel() string {
+	return "container_label_com_amazonaws_ecs_container_name"
+}
+
+func (o ecsOrchestrator) ContainerLabelFilter(resourceNames *ResourceNames, containerIndex int) string {
+	return getEcsContainerLabelFilter(resourceNames.EcsCluster, resourceNames.EcsTaskDefinition, resourceNames.Containers[containerIndex])
+}
+
+func (o ecsOrchestrator) PodLabelFilter(resourceNames *ResourceNames) string {
+	return getEcsTaskDefinitionLabelFilter(resourceNames.EcsCluster, resourceNames.EcsTaskDefinition)
+}
+
+func (o ecsOrchestrator) ContainerCpuQueries(resourceNames *ResourceNames, containerIndex int) ResourceUsageQueries {
+	labelFilter := o.ContainerLabelFilter(resourceNames, containerIndex)
+
+	return ResourceUsageQueries{
+		Requests: fmt.Sprintf(`max(container_spec_cpu_shares{%s})`, labelFilter),
+		Limits:   fmt.Sprintf(`max(container_spec_cpu_shares{%s})`, labelFilter),
+		Average:  fmt.Sprintf(`avg(sum(rate(container_cpu_usage_seconds_total{%s}[$__rate_interval])) by (id))*1024`, labelFilter),
+		Maximum:  fmt.Sprintf(`max(sum(rate(container_cpu_usage_seconds_total{%s}[$__rate_interval])) by (id))*1024`, labelFilter),
+		Minimum:  fmt.Sprintf(`min(sum(rate(container_cpu_usage_seconds_total{%s}[$__rate_interval])) by (id))*1024`, labelFilter),
+	}
+}
+
+func (o ecsOrchestrator) ContainerMemoryQueries(resourceNames *ResourceNames, containerIndex int) ResourceUsageQueries {
+	containerLabel := o.ContainerLabel()
+	labelFilter := o.ContainerLabelFilter(resourceNames, containerIndex)
+
+	return ResourceUsageQueries{
+		Requests: fmt.Sprintf(`max(container_spec_memory_reservation_limit_bytes{%s})`, labelFilter),
+		Limits:   fmt.Sprintf(`max(container_spec_memory_limit_bytes{%s})`, labelFilter),
+		Average:  fmt.Sprintf(`avg by (%s) (container_memory_rss{%s})`, containerLabel, labelFilter),
+		Maximum:  fmt.Sprintf(`max by (%s) (container_memory_rss{%s})`, containerLabel, labelFilter),
+		Minimum:  fmt.Sprintf(`min by (%s) (container_memory_rss{%s})`, containerLabel, labelFilter),
+	}
+}
+
+func (o ecsOrchestrator) ServiceUtilizationQueries(resourceNames *ResourceNames) (cpuQuery string, memoryQuery string) {
+	containerLabel := o.ContainerLabel()
+	podLabelFilter := o.PodLabelFilter(resourceNames)
+
+	cpuQuery = fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{%s}[$__rate_interval])) by (%s)/(sum(container_spec_cpu_shares{%s}) by (%s)/1024)*100`, podLabelFilter, containerLabel, podLabelFilter, containerLabel)
+	memoryQuery = fmt.Sprintf(`sum(container_memory_rss{%s}) by (%s)/sum(container_spec_memory_reservation_limit_bytes{%s}) by (%s)*100`, podLabelFilter, containerLabel, podLabelFilter, containerLabel)
+
+	return cpuQuery, memoryQuery
+}
+
+// ReplicaQuery counts the tasks by their arn, so it doesn't depend on a container which might be a template variable
+func (o ecsOrchestrator) ReplicaQuery(resourceNames *ResourceNames) string {
+	return fmt.Sprintf(`count(count by (container_label_com_amazonaws_ecs_task_arn) (container_cpu_load_average_10s{%s}))`, o.PodLabelFilter(resourceNames))
+}
+
+func (o ecsOrchestrator) ResourceUsagePanels(resourceNames *ResourceNames) []PanelFactory {
+	return newPrometheusResourceUsagePanels(resourceNames)
+}
+
+// ReplicaOverviewPanel only shows the running tasks, cadvisor doesn't know about the desired count of the service
+func (o ecsOrchestrator) ReplicaOverviewPanel(resourceNames *ResourceNames) PanelFactory {
+	return NewPanelOverviewReplicas(o.ReplicaQuery(resourceNames), "")
+}
+
+func (o ecsOrchestrator) DeploymentAnnotation(resourceNames *ResourceNames) (DashboardAnnotation, bool) {
+	// a new revision of the task definition shows up as a new value of the version label of the containers
+	versionLabel := "container_label_com_amazonaws_ecs_task_definition_version"
+	labelFilter := o.PodLabelFilter(resourceNames)
+	expression := fmt.Sprintf(`count by (%s) (container_last_seen{%s}) unless count by (%s) (container_last_seen{%s} offset 5m)`, versionLabel, labelFilter, versionLabel, labelFilter)
+	textFormat := fmt.Sprintf("%s revision {{%s}}", resourceNames.EcsTaskDefinition, versionLabel)
+
+	return NewDeploymentAnnotation(expression, textFormat), true
+}
+
+func (o ecsOrchestrator) DefaultTitle(resourceNames *ResourceNames) string {
+	return resourceNames.EcsTaskDefinition
+}
+
+func (o ecsOrchestrator) UsesContainers() bool {
+	return true
+}
+
+func (o ecsOrchestrator) DiscoverResources(ctx context.Context, settings OrchestratorSettings, appId AppId, resourceNames *ResourceNames) error {
+	ecsClient, err := discoverEcsService(ctx, settings, appId, resourceNames)
+	if err != nil {
+		return err
+	}
+
+	if len(resourceNames.Containers) > 0 {
+		return nil
+	}
+
+	excludes, err := compileExcludeContainers(settings.ExcludeContainers)
+	if err != nil {
+		return err
+	}
+
+	if resourceNames.Containers, err = ecsClient.GetContainerNames(ctx, excludes); err != nil {
+		return fmt.Errorf("can not get container names from the ecs task definition: %w", err)
+	}
+
+	return nil
+}
+
+// discoverEcsService fills the cluster, service, task definition and target groups of the ecs service
+func discoverEcsService(ctx context.Context, settings OrchestratorSettings, appId AppId, resourceNames *ResourceNames) (*EcsClient, error) {
+	resourceNames.EcsCluster = Augment(settings.NamePatterns.EcsCluster, appId)
+	resourceNames.EcsService = Augment(settings.NamePatterns.EcsService, appId)
+
+	ecsClient := NewEcsClient(settings.AwsClients, resourceNames.EcsCluster, resourceNames.EcsService)
+
+	targetGroups, err := ecsClient.GetElbTargetGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can not get target groups: %w", err)
+	}
+
+	taskDefinitionName, err := ecsClient.GetTaskDefinitionName(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can not get ecs task definition name: %w", err)
+	}
+
+	resourceNames.TargetGroups = targetGroups
+	resourceNames.EcsTaskDefinition = *taskDefinitionName
+
+	return ecsClient, nil
+}
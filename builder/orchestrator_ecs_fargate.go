@@ -0,0 +1,42 @@
+package builder
+
+import "context"
+
+// ecsFargateOrchestrator discovers its resources like ecs, but renders the resource usage from container insights
+// as fargate tasks are not scraped by cadvisor and therefore have no container_label_com_amazonaws_ecs_* labels.
+type ecsFargateOrchestrator struct {
+	ecsOrchestrator
+}
+
+func (o ecsFargateOrchestrator) Name() string {
+	return orchestratorEcsFargate
+}
+
+func (o ecsFargateOrchestrator) ResourceUsagePanels(_ *ResourceNames) []PanelFactory {
+	return []PanelFactory{
+		NewPanelContainerInsightsServiceUtilization,
+		NewPanelContainerInsightsRunningTasks,
+		NewPanelContainerInsightsCpu,
+		NewPanelContainerInsightsMemory,
+	}
+}
+
+func (o ecsFargateOrchestrator) ReplicaOverviewPanel(_ *ResourceNames) PanelFactory {
+	return NewPanelOverviewContainerInsightsTasks
+}
+
+func (o ecsFargateOrchestrator) DeploymentAnnotation(_ *ResourceNames) (DashboardAnnotation, bool) {
+	// without cadvisor there are no prometheus metrics of the task definition revisions
+	return DashboardAnnotation{}, false
+}
+
+func (o ecsFargateOrchestrator) UsesContainers() bool {
+	return false
+}
+
+// DiscoverResources skips the containers of the task definition, the container insights panels only query the service
+func (o ecsFargateOrchestrator) DiscoverResources(ctx context.Context, settings OrchestratorSettings, appId AppId, resourceNames *ResourceNames) error {
+	_, err := discoverEcsService(ctx, settings, appId, resourceNames)
+
+	return err
+}
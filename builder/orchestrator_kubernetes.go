@@ -0,0 +1,274 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/thoas/go-funk"
+)
+
+type kubernetesOrchestrator struct{}
+
+func getTraefikServiceLabelFilter(serviceName string) string {
+	return fmt.Sprintf(`service=%q`, serviceName)
+}
+
+// kubernetesWorkloadQueries describes how the pods and the ready replicas of a workload kind are found in prometheus
+type kubernetesWorkloadQueries struct {
+	// podNamePattern is the regular expression matching the pod names, %s is replaced by the name of the workload
+	podNamePattern string
+	// replicaQuery is formatted with the namespace and the name of the workload
+	replicaQuery string
+	// desiredReplicaQuery is formatted like the replicaQuery
+	desiredReplicaQuery string
+	// deploymentQuery returns a series whenever the workload was changed, it is formatted with the namespace and the name of the workload
+	deploymentQuery string
+}
+
+var kubernetesWorkloadKindQueries = map[string]kubernetesWorkloadQueries{
+	KubernetesWorkloadKindDeployment: {
+		podNamePattern:      `^%s-[0-9a-f]+-[0-9a-z]+$`,
+		replicaQuery:        `sum(kube_deployment_status_replicas_ready{namespace=%q, deployment=%q})`,
+		desiredReplicaQuery: `sum(kube_deployment_spec_replicas{namespace=%q, deployment=%q})`,
+		deploymentQuery:     `changes(kube_deployment_status_observed_generation{namespace=%q, deployment=%q}[2m]) > 0`,
+	},
+	KubernetesWorkloadKindStatefulSet: {
+		podNamePattern:      `^%s-[0-9]+$`,
+		replicaQuery:        `sum(kube_statefulset_status_replicas_ready{namespace=%q, statefulset=%q})`,
+		desiredReplicaQuery: `sum(kube_statefulset_replicas{namespace=%q, statefulset=%q})`,
+		deploymentQuery:     `changes(kube_statefulset_status_observed_generation{namespace=%q, statefulset=%q}[2m]) > 0`,
+	},
+	KubernetesWorkloadKindDaemonSet: {
+		podNamePattern:      `^%s-[0-9a-z]{5}$`,
+		replicaQuery:        `sum(kube_daemonset_status_number_ready{namespace=%q, daemonset=%q})`,
+		desiredReplicaQuery: `sum(kube_daemonset_status_desired_number_scheduled{namespace=%q, daemonset=%q})`,
+		deploymentQuery:     `changes(kube_daemonset_status_observed_generation{namespace=%q, daemonset=%q}[2m]) > 0`,
+	},
+	KubernetesWorkloadKindRollout: {
+		// a rollout manages its replicasets the same way a deployment does, every new revision creates a new one
+		podNamePattern:      `^%s-[0-9a-f]+-[0-9a-z]+$`,
+		replicaQuery:        `sum(rollout_info_replicas_available{namespace=%q, name=%q})`,
+		desiredReplicaQuery: `sum(rollout_info_replicas_desired{namespace=%q, name=%q})`,
+		deploymentQuery:     `kube_replicaset_created > time() - 120 and on(namespace, replicaset) kube_replicaset_owner{namespace=%q, owner_kind="Rollout", owner_name=%q}`,
+	},
+}
+
+func getKubernetesWorkloadQueries(resourceNames *ResourceNames) kubernetesWorkloadQueries {
+	if queries, ok := kubernetesWorkloadKindQueries[resourceNames.KubernetesWorkloadKind]; ok {
+		return queries
+	}
+
+	return kubernetesWorkloadKindQueries[KubernetesWorkloadKindDeployment]
+}
+
+// getKubernetesPodLabelFilter matches the pods by their name unless they are selected by the selector of the workload, then only the
+// namespace is matched and the pods are selected by joining with getKubernetesPodSelectorJoin
+func getKubernetesPodLabelFilter(resourceNames *ResourceNames) string {
+	if len(resourceNames.KubernetesPodSelector) > 0 {
+		return fmt.Sprintf(`namespace=%q`, resourceNames.KubernetesNamespace)
+	}
+
+	podNamePattern := fmt.Sprintf(getKubernetesWorkloadQueries(resourceNames).podNamePattern, resourceNames.KubernetesPod)
+
+	return fmt.Sprintf(`namespace=%q, pod=~"%s"`, resourceNames.KubernetesNamespace, podNamePattern)
+}
+
+// getKubernetesPodSelectorJoin keeps the series of the pods matching the selector of the workload, it is empty if the
+// selector is unknown. It is appended to the vectors filtered by getKubernetesPodLabelFilter.
+func getKubernetesPodSelectorJoin(resourceNames *ResourceNames) string {
+	if len(resourceNames.KubernetesPodSelector) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		` * on(namespace, pod) group_left() max by (namespace, pod) (kube_pod_labels{namespace=%q, %s})`,
+		resourceNames.KubernetesNamespace,
+		strings.Join(resourceNames.KubernetesPodSelector, ", "),
+	)
+}
+
+func getTraefikKubernetesServiceName(service KubernetesService, port KubernetesServicePort) string {
+	return fmt.Sprintf("%s-%s-%d@kubernetes", service.Namespace, service.Name, port.Port)
+}
+
+func (o kubernetesOrchestrator) Name() string {
+	return orchestratorKubernetes
+}
+
+func (o kubernetesOrchestrator) ContainerLabel() string {
+	return "container"
+}
+
+// ContainerLabelFilter matches the container of the pods, with templating the name of the container is the container variable
+func (o kubernetesOrchestrator) ContainerLabelFilter(resourceNames *ResourceNames, containerIndex int) string {
+	return fmt.Sprintf(`%s, container=~"%s"`, o.PodLabelFilter(resourceNames), resourceNames.Containers[containerIndex])
+}
+
+func (o kubernetesOrchestrator) PodLabelFilter(resourceNames *ResourceNames) string {
+	return getKubernetesPodLabelFilter(resourceNames)
+}
+
+// ContainerCpuQueries filters the series of the containers by the container label filter, the owners of the pods are
+// joined by the pod label filter as they don't have a container label
+func (o kubernetesOrchestrator) ContainerCpuQueries(resourceNames *ResourceNames, containerIndex int) ResourceUsageQueries {
+	labelFilter := o.ContainerLabelFilter(resourceNames, containerIndex)
+	podLabelFilter := o.PodLabelFilter(resourceNames)
+	selectorJoin := getKubernetesPodSelectorJoin(resourceNames)
+
+	return ResourceUsageQueries{
+		Requests: fmt.Sprintf(`max(kube_pod_container_resource_requests{resource="cpu",%s}%s)`, labelFilter, selectorJoin),
+		Limits:   fmt.Sprintf(`max(kube_pod_container_resource_limits{resource="cpu",%s}%s)`, labelFilter, selectorJoin),
+		Average: fmt.Sprintf(
+			`avg(sum(node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate{%s}%s * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, labelFilter, selectorJoin, podLabelFilter,
+		),
+		Maximum: fmt.Sprintf(
+			`max(sum(node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate{%s}%s * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, labelFilter, selectorJoin, podLabelFilter,
+		),
+		Minimum: fmt.Sprintf(
+			`min(sum(node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate{%s}%s * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, labelFilter, selectorJoin, podLabelFilter,
+		),
+	}
+}
+
+func (o kubernetesOrchestrator) ContainerMemoryQueries(resourceNames *ResourceNames, containerIndex int) ResourceUsageQueries {
+	labelFilter := o.ContainerLabelFilter(resourceNames, containerIndex)
+	podLabelFilter := o.PodLabelFilter(resourceNames)
+	selectorJoin := getKubernetesPodSelectorJoin(resourceNames)
+
+	return ResourceUsageQueries{
+		Requests: fmt.Sprintf(`max(kube_pod_container_resource_requests{resource="memory",%s}%s)`, labelFilter, selectorJoin),
+		Limits:   fmt.Sprintf(`max(kube_pod_container_resource_limits{resource="memory",%s}%s)`, labelFilter, selectorJoin),
+		Average: fmt.Sprintf(
+			`avg(sum(container_memory_working_set_bytes{image!="", %s}%s * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, labelFilter, selectorJoin, podLabelFilter,
+		),
+		Maximum: fmt.Sprintf(
+			`max(sum(container_memory_working_set_bytes{image!="", %s}%s * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, labelFilter, selectorJoin, podLabelFilter,
+		),
+		Minimum: fmt.Sprintf(
+			`min(sum(container_memory_working_set_bytes{image!="", %s}%s * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, labelFilter, selectorJoin, podLabelFilter,
+		),
+	}
+}
+
+func (o kubernetesOrchestrator) ServiceUtilizationQueries(resourceNames *ResourceNames) (cpuQuery string, memoryQuery string) {
+	podLabelFilter := o.PodLabelFilter(resourceNames)
+	selectorJoin := getKubernetesPodSelectorJoin(resourceNames)
+
+	cpuQuery = fmt.Sprintf(
+		`avg(sum(node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate{%s}%s
+			* on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod)
+			/ sum(kube_pod_container_resource_requests{resource="cpu", %s}%s
+			* on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod)
+			* 100)`, podLabelFilter, selectorJoin, podLabelFilter, podLabelFilter, selectorJoin, podLabelFilter)
+	memoryQuery = fmt.Sprintf(`
+			avg(sum(container_memory_working_set_bytes{%s, container!="", image!=""}%s
+			* on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod)
+			/ on(pod) cluster:namespace:pod_memory:active:kube_pod_container_resource_requests{resource="memory",%s})
+			* 100`, podLabelFilter, selectorJoin, podLabelFilter, podLabelFilter)
+
+	return cpuQuery, memoryQuery
+}
+
+func (o kubernetesOrchestrator) ReplicaQuery(resourceNames *ResourceNames) string {
+	replicaQuery := getKubernetesWorkloadQueries(resourceNames).replicaQuery
+
+	return fmt.Sprintf(replicaQuery, resourceNames.KubernetesNamespace, resourceNames.KubernetesDeployment)
+}
+
+func (o kubernetesOrchestrator) ResourceUsagePanels(resourceNames *ResourceNames) []PanelFactory {
+	return newPrometheusResourceUsagePanels(resourceNames)
+}
+
+func (o kubernetesOrchestrator) ReplicaOverviewPanel(resourceNames *ResourceNames) PanelFactory {
+	desiredReplicaQuery := getKubernetesWorkloadQueries(resourceNames).desiredReplicaQuery
+	desiredQuery := fmt.Sprintf(desiredReplicaQuery, resourceNames.KubernetesNamespace, resourceNames.KubernetesDeployment)
+
+	return NewPanelOverviewReplicas(o.ReplicaQuery(resourceNames), desiredQuery)
+}
+
+func (o kubernetesOrchestrator) DeploymentAnnotation(resourceNames *ResourceNames) (DashboardAnnotation, bool) {
+	deploymentQuery := getKubernetesWorkloadQueries(resourceNames).deploymentQuery
+	expression := fmt.Sprintf(deploymentQuery, resourceNames.KubernetesNamespace, resourceNames.KubernetesDeployment)
+	textFormat := fmt.Sprintf("%s %s/%s", resourceNames.KubernetesWorkloadKind, resourceNames.KubernetesNamespace, resourceNames.KubernetesDeployment)
+
+	return NewDeploymentAnnotation(expression, textFormat), true
+}
+
+func (o kubernetesOrchestrator) DefaultTitle(resourceNames *ResourceNames) string {
+	return fmt.Sprintf("%s-%s-%s", resourceNames.Environment, resourceNames.KubernetesNamespace, resourceNames.KubernetesPod)
+}
+
+func (o kubernetesOrchestrator) UsesContainers() bool {
+	return true
+}
+
+func (o kubernetesOrchestrator) DiscoverResources(ctx context.Context, settings OrchestratorSettings, appId AppId, resourceNames *ResourceNames) error {
+	resourceNames.KubernetesNamespace = Augment(settings.NamePatterns.KubernetesNamespace, appId)
+	resourceNames.KubernetesPod = Augment(settings.NamePatterns.KubernetesPod, appId)
+	resourceNames.KubernetesDeployment = resourceNames.KubernetesPod // KubernetesPod pattern is actually the workload name
+	resourceNames.KubernetesWorkloadKind = settings.KubernetesWorkloadKind
+	resourceNames.TraefikServiceName = Augment(settings.NamePatterns.TraefikServiceName, appId)
+
+	if settings.KubernetesClient == nil {
+		if resourceNames.KubernetesWorkloadKind == "" {
+			resourceNames.KubernetesWorkloadKind = KubernetesWorkloadKindDeployment
+		}
+
+		return nil
+	}
+
+	kinds := make([]string, 0, 1)
+	if settings.KubernetesWorkloadKind != "" {
+		kinds = append(kinds, settings.KubernetesWorkloadKind)
+	}
+
+	workload, err := settings.KubernetesClient.GetWorkload(ctx, resourceNames.KubernetesNamespace, resourceNames.KubernetesDeployment, kinds...)
+	if err != nil {
+		return fmt.Errorf("can not get kubernetes workload: %w", err)
+	}
+
+	resourceNames.KubernetesWorkloadKind = workload.Kind
+
+	if matchers, ok := workload.Selector.PrometheusMatchers(); ok && settings.KubernetesSelectPodsByLabels {
+		resourceNames.KubernetesPodSelector = matchers
+	}
+
+	services, err := settings.KubernetesClient.GetWorkloadServices(ctx, workload)
+	if err != nil {
+		return fmt.Errorf("can not get kubernetes services of the workload: %w", err)
+	}
+
+	resourceNames.TraefikServiceName = selectTraefikServiceName(resourceNames.TraefikServiceName, services)
+
+	if len(resourceNames.Containers) > 0 {
+		return nil
+	}
+
+	excludes, err := compileExcludeContainers(settings.ExcludeContainers)
+	if err != nil {
+		return err
+	}
+
+	resourceNames.Containers = workload.ContainerNames(excludes)
+
+	return nil
+}
+
+// selectTraefikServiceName keeps the name derived from the pattern if it belongs to one of the services selecting the
+// pods, otherwise the first port of the first of these services is used
+func selectTraefikServiceName(patternName string, services []KubernetesService) string {
+	candidates := make([]string, 0)
+
+	for _, service := range services {
+		for _, port := range service.Ports {
+			candidates = append(candidates, getTraefikKubernetesServiceName(service, port))
+		}
+	}
+
+	if len(candidates) == 0 || funk.ContainsString(candidates, patternName) {
+		return patternName
+	}
+
+	return candidates[0]
+}
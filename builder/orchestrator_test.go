@@ -0,0 +1,137 @@
+package builder_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+type testOrchestrator struct{}
+
+func (o testOrchestrator) Name() string {
+	return "test"
+}
+
+func (o testOrchestrator) ContainerLabel() string {
+	return "task"
+}
+
+func (o testOrchestrator) ContainerLabelFilter(_ *builder.ResourceNames, _ int) string {
+	return `job="app"`
+}
+
+func (o testOrchestrator) PodLabelFilter(_ *builder.ResourceNames) string {
+	return `job="app"`
+}
+
+func (o testOrchestrator) ContainerCpuQueries(_ *builder.ResourceNames, _ int) builder.ResourceUsageQueries {
+	return builder.ResourceUsageQueries{Average: "cpu_average"}
+}
+
+func (o testOrchestrator) ContainerMemoryQueries(_ *builder.ResourceNames, _ int) builder.ResourceUsageQueries {
+	return builder.ResourceUsageQueries{Average: "memory_average"}
+}
+
+func (o testOrchestrator) ServiceUtilizationQueries(_ *builder.ResourceNames) (cpuQuery string, memoryQuery string) {
+	return "cpu_utilization", "memory_utilization"
+}
+
+func (o testOrchestrator) ReplicaQuery(_ *builder.ResourceNames) string {
+	return "replicas"
+}
+
+func (o testOrchestrator) ResourceUsagePanels(_ *builder.ResourceNames) []builder.PanelFactory {
+	return []builder.PanelFactory{
+		builder.NewPanelServiceUtilization,
+		builder.NewPanelTaskDeployment,
+		builder.NewPanelContainerCpuFactory(0),
+		builder.NewPanelContainerMemoryFactory(0),
+	}
+}
+
+func (o testOrchestrator) ReplicaOverviewPanel(_ *builder.ResourceNames) builder.PanelFactory {
+	return builder.NewPanelOverviewReplicas("replicas", "")
+}
+
+func (o testOrchestrator) DeploymentAnnotation(_ *builder.ResourceNames) (builder.DashboardAnnotation, bool) {
+	return builder.NewDeploymentAnnotation("deployments", ""), true
+}
+
+func (o testOrchestrator) DefaultTitle(resourceNames *builder.ResourceNames) string {
+	return "test-" + resourceNames.Environment
+}
+
+func (o testOrchestrator) UsesContainers() bool {
+	return true
+}
+
+func (o testOrchestrator) DiscoverResources(_ context.Context, _ builder.OrchestratorSettings, _ builder.AppId, resourceNames *builder.ResourceNames) error {
+	resourceNames.Containers = []string{"app"}
+
+	return nil
+}
+
+func TestAvailableOrchestrators(t *testing.T) {
+	assert.Subset(t, builder.AvailableOrchestrators(), []string{"ecs", "kubernetes"})
+}
+
+func TestRegisterOrchestrator(t *testing.T) {
+	builder.RegisterOrchestrator(testOrchestrator{})
+
+	orchestrator, ok := builder.GetOrchestrator("test")
+	assert.True(t, ok)
+
+	resourceNames := &builder.ResourceNames{
+		Environment: "env",
+	}
+	err := orchestrator.DiscoverResources(context.Background(), builder.OrchestratorSettings{}, provideAppId(), resourceNames)
+	assert.NoError(t, err)
+
+	db := newDashboardBuilder(t, resourceNames, "test")
+	db.AddServiceAndTask()
+	dashboard := db.Build("")
+
+	assert.Equal(t, "test-env", dashboard.Title)
+	assert.Len(t, dashboard.Panels, 5)
+	assert.Equal(t, "cpu_utilization", dashboard.Panels[1].Targets[0].(builder.PanelTargetPrometheus).Expression)
+	assert.Equal(t, "replicas", dashboard.Panels[2].Targets[0].(builder.PanelTargetPrometheus).Expression)
+	assert.Equal(t, "cpu_average", dashboard.Panels[3].Targets[3].(builder.PanelTargetPrometheus).Expression)
+	assert.Equal(t, "memory_average", dashboard.Panels[4].Targets[3].(builder.PanelTargetPrometheus).Expression)
+}
+
+func TestEcsFargateResourceUsage(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		EcsCluster:                      "cluster",
+		EcsService:                      "service",
+		EcsTaskDefinition:               "task-def",
+		GrafanaCloudWatchDatasourceName: "cw",
+	}
+
+	db := newDashboardBuilder(t, resourceNames, "ecs_fargate")
+	db.AddServiceAndTask()
+	dashboard := db.Build("")
+
+	body, err := json.Marshal(dashboard)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "task-def", dashboard.Title)
+	assert.Len(t, dashboard.Panels, 5)
+	assert.NotContains(t, string(body), "container_label_com_amazonaws_ecs")
+
+	for _, panel := range dashboard.Panels[1:] {
+		assert.Equal(t, "cw", panel.Datasource)
+
+		for _, target := range panel.Targets {
+			cwTarget := target.(builder.PanelTargetCloudWatch)
+			if cwTarget.Expression != "" {
+				continue
+			}
+
+			assert.Equal(t, "ECS/ContainerInsights", cwTarget.Namespace)
+			assert.Equal(t, map[string]string{"ClusterName": "cluster", "ServiceName": "service"}, cwTarget.Dimensions)
+		}
+	}
+}
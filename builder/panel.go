@@ -1,22 +1,53 @@
 package builder
 
-func newPanelSettings(resourceNames *ResourceNames, gridPos PanelGridPos, orchestrator string) PanelSettings {
+import "encoding/json"
+
+func newPanelSettings(
+	resourceNames *ResourceNames,
+	gridPos PanelGridPos,
+	orchestrator Orchestrator,
+	templating bool,
+	percentiles []Percentile,
+	theme Theme,
+	thresholds Thresholds,
+) PanelSettings {
 	return PanelSettings{
 		resourceNames: resourceNames,
 		gridPos:       gridPos,
 		orchestrator:  orchestrator,
+		templating:    templating,
+		percentiles:   percentiles,
+		theme:         theme,
+		thresholds:    thresholds,
 	}
 }
 
 type PanelSettings struct {
 	resourceNames *ResourceNames
 	gridPos       PanelGridPos
-	orchestrator  string
+	orchestrator  Orchestrator
+	// templating is set if the dashboard has template variables, the resource names then reference them where possible
+	templating bool
+	// percentiles are shown next to the average of the response times
+	percentiles []Percentile
+	theme       Theme
+	thresholds  Thresholds
 }
 
 type PanelFactory func(settings PanelSettings) Panel
 
+// repeatPanel lets grafana repeat the panel, or the row with its panels, for every selected value of the variable
+func repeatPanel(factory PanelFactory, variable string) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		panel := factory(settings)
+		panel.Repeat = variable
+
+		return panel
+	}
+}
+
 type Panel struct {
+	Id          int              `json:"id"`
 	Collapsed   bool             `json:"collapsed,omitempty"`
 	Datasource  string           `json:"datasource"`
 	FieldConfig PanelFieldConfig `json:"fieldConfig"`
@@ -26,6 +57,35 @@ type Panel struct {
 	Title       string           `json:"title"`
 	Type        string           `json:"type"`
 	Panels      []Panel          `json:"panels"`
+	Repeat      string           `json:"repeat,omitempty"`
+	// Raw is the json of a panel supplied by the user, it is emitted as it is apart from the id and the grid position
+	Raw             map[string]any        `json:"-"`
+	RepeatDirection string                `json:"repeatDirection,omitempty"`
+	Transformations []PanelTransformation `json:"transformations,omitempty"`
+}
+
+type PanelTransformation struct {
+	Id      string `json:"id"`
+	Options any    `json:"options"`
+}
+
+func (p Panel) MarshalJSON() ([]byte, error) {
+	// the conversion drops the methods of the panel and avoids the recursion
+	type panel Panel
+
+	if p.Raw == nil {
+		return json.Marshal(panel(p))
+	}
+
+	raw := make(map[string]any, len(p.Raw)+2)
+	for key, value := range p.Raw {
+		raw[key] = value
+	}
+
+	raw["id"] = p.Id
+	raw["gridPos"] = p.GridPos
+
+	return json.Marshal(raw)
 }
 
 type PanelFieldConfig struct {
@@ -136,6 +196,29 @@ type PanelOptionsTooltip struct {
 	Mode string `json:"mode"`
 }
 
+type PanelOptionsStat struct {
+	ColorMode     string             `json:"colorMode"`
+	GraphMode     string             `json:"graphMode"`
+	JustifyMode   string             `json:"justifyMode"`
+	Orientation   string             `json:"orientation"`
+	ReduceOptions PanelOptionsReduce `json:"reduceOptions"`
+	TextMode      string             `json:"textMode"`
+}
+
+type PanelOptionsGauge struct {
+	Orientation          string             `json:"orientation"`
+	ReduceOptions        PanelOptionsReduce `json:"reduceOptions"`
+	ShowThresholdLabels  bool               `json:"showThresholdLabels"`
+	ShowThresholdMarkers bool               `json:"showThresholdMarkers"`
+}
+
+// PanelOptionsReduce reduces the series of a stat or gauge panel to a single value, e.g. "lastNotNull" or "sum"
+type PanelOptionsReduce struct {
+	Calcs  []string `json:"calcs"`
+	Fields string   `json:"fields"`
+	Values bool     `json:"values"`
+}
+
 type PanelTargetCloudWatch struct {
 	Alias      string            `json:"alias"`
 	Dimensions map[string]string `json:"dimensions"`
@@ -187,3 +270,13 @@ type PanelOptionsElasticsearch struct {
 	DedupStrategy      string `json:"dedupStrategy"`
 	SortOrder          string `json:"sortOrder"`
 }
+
+type PanelOptionsTable struct {
+	ShowHeader bool                      `json:"showHeader"`
+	SortBy     []PanelOptionsTableSortBy `json:"sortBy"`
+}
+
+type PanelOptionsTableSortBy struct {
+	Desc        bool   `json:"desc"`
+	DisplayName string `json:"displayName"`
+}
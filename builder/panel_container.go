@@ -6,77 +6,24 @@ const (
 	datasourcePrometheus = "prometheus"
 )
 
-func getTraefikServiceLabelFilter(serviceName string) string {
-	return fmt.Sprintf(`service=%q`, serviceName)
-}
-
-func getKubernetesPodLabelFilter(namespace, podName string) string {
-	return fmt.Sprintf(`namespace=%q, pod=~"^%s-[0-9a-f]+-[0-9a-z]+$"`, namespace, podName)
-}
-
-func getKubernetesDeploymentLabelFilter(namespace, deploymentName string) string {
-	return fmt.Sprintf(`namespace=%q, deployment=%q`, namespace, deploymentName)
-}
-
-func getEcsContainerLabelFilter(ecsClusterName, ecsTaskDefinitionName, containerName string) string {
-	return fmt.Sprintf(`container_label_com_amazonaws_ecs_cluster=%q, container_label_com_amazonaws_ecs_task_definition_family=%q, container_label_com_amazonaws_ecs_container_name=%q`, ecsClusterName, ecsTaskDefinitionName, containerName)
-}
-
-func getEcsTaskDefinitionLabelFilter(ecsClusterName, ecsTaskDefinitionName string) string {
-	return fmt.Sprintf(`container_label_com_amazonaws_ecs_cluster=%q, container_label_com_amazonaws_ecs_task_definition_family=%q`, ecsClusterName, ecsTaskDefinitionName)
-}
-
-func getLabelAndFilters(settings PanelSettings, containerIndex int) (containerLabel string, containerLabelFilter string, podLabelFilter string) {
-	switch settings.orchestrator {
-	case orchestratorEcs:
-		containerLabel = "container_label_com_amazonaws_ecs_container_name"
-		containerLabelFilter = getEcsContainerLabelFilter(settings.resourceNames.EcsCluster, settings.resourceNames.EcsTaskDefinition, settings.resourceNames.Containers[containerIndex])
-		podLabelFilter = getEcsTaskDefinitionLabelFilter(settings.resourceNames.EcsCluster, settings.resourceNames.EcsTaskDefinition)
-	case orchestratorKubernetes:
-		containerLabel = "container"
-		containerLabelFilter = getKubernetesPodLabelFilter(settings.resourceNames.KubernetesNamespace, settings.resourceNames.KubernetesPod)
-		podLabelFilter = getKubernetesPodLabelFilter(settings.resourceNames.KubernetesNamespace, settings.resourceNames.KubernetesPod)
+// repeatPanelPerContainer lets grafana repeat the panel for every selected container if the container is a template variable
+func repeatPanelPerContainer(settings PanelSettings, panel Panel) Panel {
+	if settings.templating {
+		panel.Repeat = TemplateVariableContainer
+		panel.RepeatDirection = "v"
 	}
 
-	return containerLabel, containerLabelFilter, podLabelFilter
+	return panel
 }
 
 func NewPanelContainerCpuFactory(containerIndex int) PanelFactory {
 	return func(settings PanelSettings) Panel {
-		return newPanelContainerCpu(settings, containerIndex)
+		return repeatPanelPerContainer(settings, newPanelContainerCpu(settings, containerIndex))
 	}
 }
 
 func newPanelContainerCpu(settings PanelSettings, containerIndex int) Panel {
-	var labelFilter string
-	var averageQuery string
-	var requestsQuery string
-	var limitsQuery string
-	var minimumQuery string
-	var maximumQuery string
-
-	switch settings.orchestrator {
-	case orchestratorEcs:
-		labelFilter = getEcsContainerLabelFilter(settings.resourceNames.EcsCluster, settings.resourceNames.EcsTaskDefinition, settings.resourceNames.Containers[containerIndex])
-		requestsQuery = fmt.Sprintf(`max(container_spec_cpu_shares{%s})`, labelFilter)
-		limitsQuery = fmt.Sprintf(`max(container_spec_cpu_shares{%s})`, labelFilter)
-		averageQuery = fmt.Sprintf(`avg(sum(rate(container_cpu_usage_seconds_total{%s}[$__rate_interval])) by (id))*1024`, labelFilter)
-		maximumQuery = fmt.Sprintf(`max(sum(rate(container_cpu_usage_seconds_total{%s}[$__rate_interval])) by (id))*1024`, labelFilter)
-		minimumQuery = fmt.Sprintf(`min(sum(rate(container_cpu_usage_seconds_total{%s}[$__rate_interval])) by (id))*1024`, labelFilter)
-	case orchestratorKubernetes:
-		labelFilter = getKubernetesPodLabelFilter(settings.resourceNames.KubernetesNamespace, settings.resourceNames.KubernetesPod)
-		requestsQuery = fmt.Sprintf(`max(kube_pod_container_resource_requests{resource="cpu",%s})`, labelFilter)
-		limitsQuery = fmt.Sprintf(`max(kube_pod_container_resource_limits{resource="cpu",%s})`, labelFilter)
-		averageQuery = fmt.Sprintf(
-			`avg(sum(node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate{%s} * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, labelFilter, labelFilter,
-		)
-		maximumQuery = fmt.Sprintf(
-			`max(sum(node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate{%s} * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, labelFilter, labelFilter,
-		)
-		minimumQuery = fmt.Sprintf(
-			`min(sum(node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate{%s} * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, labelFilter, labelFilter,
-		)
-	}
+	queries := settings.orchestrator.ContainerCpuQueries(settings.resourceNames, containerIndex)
 
 	return Panel{
 		Datasource: datasourcePrometheus,
@@ -85,42 +32,42 @@ func newPanelContainerCpu(settings PanelSettings, containerIndex int) Panel {
 				Min: "0",
 			},
 			Overrides: []PanelFieldConfigOverride{
-				NewColorPropertyOverride("Requests", "red", "dash"),
-				NewColorPropertyOverride("Limits", "orange", "dash"),
-				NewColorPropertyOverride("Minimum", "light-green", ""),
-				NewColorPropertyOverride("Average", "light-orange", ""),
-				NewColorPropertyOverride("Maximum", "light-red", ""),
+				NewColorPropertyOverride("Requests", settings.theme.Color(ThemeRoleCpuRequests), "dash"),
+				NewColorPropertyOverride("Limits", settings.theme.Color(ThemeRoleResourceLimits), "dash"),
+				NewColorPropertyOverride("Minimum", settings.theme.Color(ThemeRoleMinimum), ""),
+				NewColorPropertyOverride("Average", settings.theme.Color(ThemeRoleAverage), ""),
+				NewColorPropertyOverride("Maximum", settings.theme.Color(ThemeRoleMaximum), ""),
 			},
 		},
 		GridPos: settings.gridPos,
 		Targets: []any{
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   requestsQuery,
+				Expression:   queries.Requests,
 				LegendFormat: "Requests",
 				RefId:        "requests",
 			},
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   limitsQuery,
+				Expression:   queries.Limits,
 				LegendFormat: "Limits",
 				RefId:        "limits",
 			},
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   minimumQuery,
+				Expression:   queries.Minimum,
 				LegendFormat: "Minimum",
 				RefId:        "minimum",
 			},
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   averageQuery,
+				Expression:   queries.Average,
 				LegendFormat: "Average",
 				RefId:        "average",
 			},
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   maximumQuery,
+				Expression:   queries.Maximum,
 				LegendFormat: "Maximum",
 				RefId:        "maximum",
 			},
@@ -133,38 +80,12 @@ func newPanelContainerCpu(settings PanelSettings, containerIndex int) Panel {
 
 func NewPanelContainerMemoryFactory(containerIndex int) PanelFactory {
 	return func(settings PanelSettings) Panel {
-		return newPanelContainerMemory(settings, containerIndex)
+		return repeatPanelPerContainer(settings, newPanelContainerMemory(settings, containerIndex))
 	}
 }
 
 func newPanelContainerMemory(settings PanelSettings, containerIndex int) Panel {
-	containerLabel, containerLabelFilter, podLabelFilter := getLabelAndFilters(settings, containerIndex)
-	var averageQuery string
-	var requestsQuery string
-	var limitsQuery string
-	var minimumQuery string
-	var maximumQuery string
-
-	switch settings.orchestrator {
-	case orchestratorEcs:
-		requestsQuery = fmt.Sprintf(`max(container_spec_memory_reservation_limit_bytes{%s})`, containerLabelFilter)
-		limitsQuery = fmt.Sprintf(`max(container_spec_memory_limit_bytes{%s})`, containerLabelFilter)
-		averageQuery = fmt.Sprintf(`avg by (%s) (container_memory_rss{%s})`, containerLabel, containerLabelFilter)
-		maximumQuery = fmt.Sprintf(`max by (%s) (container_memory_rss{%s})`, containerLabel, containerLabelFilter)
-		minimumQuery = fmt.Sprintf(`min by (%s) (container_memory_rss{%s})`, containerLabel, containerLabelFilter)
-	case orchestratorKubernetes:
-		requestsQuery = fmt.Sprintf(`max(kube_pod_container_resource_requests{resource="memory",%s})`, podLabelFilter)
-		limitsQuery = fmt.Sprintf(`max(kube_pod_container_resource_limits{resource="memory",%s})`, podLabelFilter)
-		averageQuery = fmt.Sprintf(
-			`avg(sum(container_memory_working_set_bytes{container!="", image!="", %s} * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, podLabelFilter, podLabelFilter,
-		)
-		maximumQuery = fmt.Sprintf(
-			`max(sum(container_memory_working_set_bytes{container!="", image!="", %s} * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, podLabelFilter, podLabelFilter,
-		)
-		minimumQuery = fmt.Sprintf(
-			`min(sum(container_memory_working_set_bytes{container!="", image!="", %s} * on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod))`, podLabelFilter, podLabelFilter,
-		)
-	}
+	queries := settings.orchestrator.ContainerMemoryQueries(settings.resourceNames, containerIndex)
 
 	return Panel{
 		Datasource: datasourcePrometheus,
@@ -174,42 +95,42 @@ func newPanelContainerMemory(settings PanelSettings, containerIndex int) Panel {
 				Unit: "bytes",
 			},
 			Overrides: []PanelFieldConfigOverride{
-				NewColorPropertyOverride("Requests", "semi-dark-red", "dash"),
-				NewColorPropertyOverride("Limits", "orange", "dash"),
-				NewColorPropertyOverride("Minimum", "light-green", ""),
-				NewColorPropertyOverride("Average", "light-orange", ""),
-				NewColorPropertyOverride("Maximum", "light-red", ""),
+				NewColorPropertyOverride("Requests", settings.theme.Color(ThemeRoleMemoryRequests), "dash"),
+				NewColorPropertyOverride("Limits", settings.theme.Color(ThemeRoleResourceLimits), "dash"),
+				NewColorPropertyOverride("Minimum", settings.theme.Color(ThemeRoleMinimum), ""),
+				NewColorPropertyOverride("Average", settings.theme.Color(ThemeRoleAverage), ""),
+				NewColorPropertyOverride("Maximum", settings.theme.Color(ThemeRoleMaximum), ""),
 			},
 		},
 		GridPos: settings.gridPos,
 		Targets: []any{
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   requestsQuery,
+				Expression:   queries.Requests,
 				LegendFormat: "Requests",
 				RefId:        "requests",
 			},
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   limitsQuery,
+				Expression:   queries.Limits,
 				LegendFormat: "Limits",
 				RefId:        "limits",
 			},
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   minimumQuery,
+				Expression:   queries.Minimum,
 				LegendFormat: "Minimum",
 				RefId:        "minimum",
 			},
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   averageQuery,
+				Expression:   queries.Average,
 				LegendFormat: "Average",
 				RefId:        "average",
 			},
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   maximumQuery,
+				Expression:   queries.Maximum,
 				LegendFormat: "Maximum",
 				RefId:        "maximum",
 			},
@@ -221,27 +142,8 @@ func newPanelContainerMemory(settings PanelSettings, containerIndex int) Panel {
 }
 
 func NewPanelServiceUtilization(settings PanelSettings) Panel {
-	containerLabel, _, podLabelFilter := getLabelAndFilters(settings, 0)
-	var cpuAverageQuery string
-	var memoryAverageQuery string
-
-	switch settings.orchestrator {
-	case orchestratorEcs:
-		cpuAverageQuery = fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{%s}[$__rate_interval])) by (%s)/(sum(container_spec_cpu_shares{%s}) by (%s)/1024)*100`, podLabelFilter, containerLabel, podLabelFilter, containerLabel)
-		memoryAverageQuery = fmt.Sprintf(`sum(container_memory_rss{%s}) by (%s)/sum(container_spec_memory_reservation_limit_bytes{%s}) by (%s)*100`, podLabelFilter, containerLabel, podLabelFilter, containerLabel)
-	case orchestratorKubernetes:
-		cpuAverageQuery = fmt.Sprintf(
-			`avg(sum(node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate{%s}
-			* on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod)
-			/ sum(kube_pod_container_resource_requests{resource="cpu", %s}
-			* on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod)
-			* 100)`, podLabelFilter, podLabelFilter, podLabelFilter, podLabelFilter)
-		memoryAverageQuery = fmt.Sprintf(`
-			avg(sum(container_memory_working_set_bytes{%s, container!="", image!=""}
-			* on(namespace,pod) group_left(workload, workload_type) namespace_workload_pod:kube_pod_owner:relabel{%s}) by (pod)
-			/ on(pod) cluster:namespace:pod_memory:active:kube_pod_container_resource_requests{resource="memory",%s})
-			* 100`, podLabelFilter, podLabelFilter, podLabelFilter)
-	}
+	containerLabel := settings.orchestrator.ContainerLabel()
+	cpuAverageQuery, memoryAverageQuery := settings.orchestrator.ServiceUtilizationQueries(settings.resourceNames)
 
 	cpuAverageLegendFormat := fmt.Sprintf("CPU Average {{%s}}", containerLabel)
 	memoryAverageLegendFormat := fmt.Sprintf("Memory Average {{%s}}", containerLabel)
@@ -251,28 +153,15 @@ func NewPanelServiceUtilization(settings PanelSettings) Panel {
 		FieldConfig: PanelFieldConfig{
 			Defaults: PanelFieldConfigDefaults{
 				Custom: PanelFieldConfigDefaultsCustom{
-					ThresholdsStyle: ThresholdsStyle{
-						Mode: "line",
-					},
-				},
-				Max: "200",
-				Min: "0",
-				Thresholds: PanelFieldConfigDefaultsThresholds{
-					Mode: "absolute",
-					Steps: []PanelFieldConfigDefaultsThresholdsStep{
-						{
-							Color: "super-light-green",
-						},
-						{
-							Color: "semi-dark-red",
-							Value: 100,
-						},
-					},
+					ThresholdsStyle: newPanelThresholdsStyle(settings, PanelKindServiceUtilization),
 				},
-				Unit: "percent",
+				Max:        "200",
+				Min:        "0",
+				Thresholds: newPanelThresholdLines(settings, PanelKindServiceUtilization),
+				Unit:       "percent",
 			},
 			Overrides: []PanelFieldConfigOverride{
-				NewColorPropertyOverride("CPU Average ", "light-green", ""), // the trailing slash seems to be important for grafana to match the override due to omitting the {{foo}} part
+				NewColorPropertyOverride("CPU Average ", settings.theme.Color(ThemeRoleCpu), ""), // the trailing slash seems to be important for grafana to match the override due to omitting the {{foo}} part
 			},
 		},
 		GridPos: settings.gridPos,
@@ -297,17 +186,7 @@ func NewPanelServiceUtilization(settings PanelSettings) Panel {
 }
 
 func NewPanelTaskDeployment(settings PanelSettings) Panel {
-	var labelFilter string
-	var query string
-
-	switch settings.orchestrator {
-	case orchestratorEcs:
-		labelFilter = getEcsContainerLabelFilter(settings.resourceNames.EcsCluster, settings.resourceNames.EcsTaskDefinition, settings.resourceNames.Containers[0])
-		query = fmt.Sprintf(`count(container_cpu_load_average_10s{%s})`, labelFilter)
-	case orchestratorKubernetes:
-		labelFilter = getKubernetesDeploymentLabelFilter(settings.resourceNames.KubernetesNamespace, settings.resourceNames.KubernetesDeployment)
-		query = fmt.Sprintf("sum(kube_deployment_status_replicas_ready{%s})", labelFilter)
-	}
+	query := settings.orchestrator.ReplicaQuery(settings.resourceNames)
 
 	return Panel{
 		Datasource: datasourcePrometheus,
@@ -0,0 +1,227 @@
+package builder
+
+const namespaceContainerInsights = "ECS/ContainerInsights"
+
+func getContainerInsightsDimensions(settings PanelSettings) map[string]string {
+	return map[string]string{
+		"ClusterName": settings.resourceNames.EcsCluster,
+		"ServiceName": settings.resourceNames.EcsService,
+	}
+}
+
+func NewPanelContainerInsightsServiceUtilization(settings PanelSettings) Panel {
+	return Panel{
+		Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+		FieldConfig: PanelFieldConfig{
+			Defaults: PanelFieldConfigDefaults{
+				Custom: PanelFieldConfigDefaultsCustom{
+					ThresholdsStyle: newPanelThresholdsStyle(settings, PanelKindServiceUtilization),
+				},
+				Max:        "200",
+				Min:        "0",
+				Thresholds: newPanelThresholdLines(settings, PanelKindServiceUtilization),
+				Unit:       "percent",
+			},
+			Overrides: []PanelFieldConfigOverride{
+				NewColorPropertyOverride("CPU Average", settings.theme.Color(ThemeRoleCpu), ""),
+				NewColorPropertyOverride("Memory Average", settings.theme.Color(ThemeRoleMemory), ""),
+			},
+		},
+		GridPos: settings.gridPos,
+		Targets: []any{
+			PanelTargetCloudWatch{
+				Dimensions: getContainerInsightsDimensions(settings),
+				Id:         "cpu_utilized",
+				Hide:       true,
+				MatchExact: true,
+				MetricName: "CpuUtilized",
+				Namespace:  namespaceContainerInsights,
+				RefId:      "A",
+				Region:     "default",
+				Statistics: []string{
+					"Average",
+				},
+			},
+			PanelTargetCloudWatch{
+				Dimensions: getContainerInsightsDimensions(settings),
+				Id:         "cpu_reserved",
+				Hide:       true,
+				MatchExact: true,
+				MetricName: "CpuReserved",
+				Namespace:  namespaceContainerInsights,
+				RefId:      "B",
+				Region:     "default",
+				Statistics: []string{
+					"Average",
+				},
+			},
+			PanelTargetCloudWatch{
+				Alias:      "CPU Average",
+				Dimensions: map[string]string{},
+				Expression: "100*cpu_utilized/cpu_reserved",
+				RefId:      "C",
+				Region:     "default",
+			},
+			PanelTargetCloudWatch{
+				Dimensions: getContainerInsightsDimensions(settings),
+				Id:         "memory_utilized",
+				Hide:       true,
+				MatchExact: true,
+				MetricName: "MemoryUtilized",
+				Namespace:  namespaceContainerInsights,
+				RefId:      "D",
+				Region:     "default",
+				Statistics: []string{
+					"Average",
+				},
+			},
+			PanelTargetCloudWatch{
+				Dimensions: getContainerInsightsDimensions(settings),
+				Id:         "memory_reserved",
+				Hide:       true,
+				MatchExact: true,
+				MetricName: "MemoryReserved",
+				Namespace:  namespaceContainerInsights,
+				RefId:      "E",
+				Region:     "default",
+				Statistics: []string{
+					"Average",
+				},
+			},
+			PanelTargetCloudWatch{
+				Alias:      "Memory Average",
+				Dimensions: map[string]string{},
+				Expression: "100*memory_utilized/memory_reserved",
+				RefId:      "F",
+				Region:     "default",
+			},
+		},
+		Options: &PanelOptionsCloudWatch{},
+		Title:   "Service Utilization",
+		Type:    "timeseries",
+	}
+}
+
+func NewPanelContainerInsightsRunningTasks(settings PanelSettings) Panel {
+	return Panel{
+		Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+		FieldConfig: PanelFieldConfig{
+			Defaults: PanelFieldConfigDefaults{
+				Min: "0",
+			},
+			Overrides: []PanelFieldConfigOverride{
+				NewColorPropertyOverride("Desired", settings.theme.Color(ThemeRoleDesired), "dash"),
+			},
+		},
+		GridPos: settings.gridPos,
+		Targets: []any{
+			PanelTargetCloudWatch{
+				Alias:      "RunningTaskCount",
+				Dimensions: getContainerInsightsDimensions(settings),
+				MatchExact: true,
+				MetricName: "RunningTaskCount",
+				Namespace:  namespaceContainerInsights,
+				RefId:      "A",
+				Region:     "default",
+				Statistics: []string{
+					"Average",
+				},
+			},
+			PanelTargetCloudWatch{
+				Alias:      "Desired",
+				Dimensions: getContainerInsightsDimensions(settings),
+				MatchExact: true,
+				MetricName: "DesiredTaskCount",
+				Namespace:  namespaceContainerInsights,
+				RefId:      "B",
+				Region:     "default",
+				Statistics: []string{
+					"Average",
+				},
+			},
+		},
+		Options: &PanelOptionsCloudWatch{},
+		Title:   "Running Task Count",
+		Type:    "timeseries",
+	}
+}
+
+func NewPanelContainerInsightsCpu(settings PanelSettings) Panel {
+	return newPanelContainerInsightsUsage(settings, "CPU Utilization", "CpuUtilized", "CpuReserved", "")
+}
+
+func NewPanelContainerInsightsMemory(settings PanelSettings) Panel {
+	return newPanelContainerInsightsUsage(settings, "Memory Utilization", "MemoryUtilized", "MemoryReserved", "decmbytes")
+}
+
+func newPanelContainerInsightsUsage(settings PanelSettings, title string, utilizedMetric string, reservedMetric string, unit string) Panel {
+	return Panel{
+		Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+		FieldConfig: PanelFieldConfig{
+			Defaults: PanelFieldConfigDefaults{
+				Min:  "0",
+				Unit: unit,
+			},
+			Overrides: []PanelFieldConfigOverride{
+				NewColorPropertyOverride("Reserved", settings.theme.Color(ThemeRoleReservation), "dash"),
+				NewColorPropertyOverride("Minimum", settings.theme.Color(ThemeRoleMinimum), ""),
+				NewColorPropertyOverride("Average", settings.theme.Color(ThemeRoleAverage), ""),
+				NewColorPropertyOverride("Maximum", settings.theme.Color(ThemeRoleMaximum), ""),
+			},
+		},
+		GridPos: settings.gridPos,
+		Targets: []any{
+			PanelTargetCloudWatch{
+				Alias:      "Reserved",
+				Dimensions: getContainerInsightsDimensions(settings),
+				MatchExact: true,
+				MetricName: reservedMetric,
+				Namespace:  namespaceContainerInsights,
+				RefId:      "A",
+				Region:     "default",
+				Statistics: []string{
+					"Average",
+				},
+			},
+			PanelTargetCloudWatch{
+				Alias:      "Minimum",
+				Dimensions: getContainerInsightsDimensions(settings),
+				MatchExact: true,
+				MetricName: utilizedMetric,
+				Namespace:  namespaceContainerInsights,
+				RefId:      "B",
+				Region:     "default",
+				Statistics: []string{
+					"Minimum",
+				},
+			},
+			PanelTargetCloudWatch{
+				Alias:      "Average",
+				Dimensions: getContainerInsightsDimensions(settings),
+				MatchExact: true,
+				MetricName: utilizedMetric,
+				Namespace:  namespaceContainerInsights,
+				RefId:      "C",
+				Region:     "default",
+				Statistics: []string{
+					"Average",
+				},
+			},
+			PanelTargetCloudWatch{
+				Alias:      "Maximum",
+				Dimensions: getContainerInsightsDimensions(settings),
+				MatchExact: true,
+				MetricName: utilizedMetric,
+				Namespace:  namespaceContainerInsights,
+				RefId:      "D",
+				Region:     "default",
+				Statistics: []string{
+					"Maximum",
+				},
+			},
+		},
+		Options: &PanelOptionsCloudWatch{},
+		Title:   title,
+		Type:    "timeseries",
+	}
+}
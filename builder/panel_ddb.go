@@ -12,8 +12,8 @@ func NewPanelDdbReadUsage(table MetadataCloudAwsDynamodbTable) PanelFactory {
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("Provisioned", "dark-red", ""),
-					NewColorPropertyOverride("Consumed", "super-light-blue", ""),
+					NewColorPropertyOverride("Provisioned", settings.theme.Color(ThemeRoleLimits), ""),
+					NewColorPropertyOverride("Consumed", settings.theme.Color(ThemeRoleTraffic), ""),
 				},
 			},
 			GridPos: settings.gridPos,
@@ -83,11 +83,13 @@ func NewPanelDdbReadThrottles(table MetadataCloudAwsDynamodbTable) PanelFactory
 			FieldConfig: PanelFieldConfig{
 				Defaults: PanelFieldConfigDefaults{
 					Custom: PanelFieldConfigDefaultsCustom{
-						AxisPlacement: "right",
-						LineWidth:     2,
-						SpanNulls:     true,
+						AxisPlacement:   "right",
+						LineWidth:       2,
+						SpanNulls:       true,
+						ThresholdsStyle: newPanelThresholdsStyle(settings, PanelKindDdbThrottles),
 					},
-					Min: "0",
+					Min:        "0",
+					Thresholds: newPanelThresholdLines(settings, PanelKindDdbThrottles),
 				},
 				Overrides: []PanelFieldConfigOverride{},
 			},
@@ -175,8 +177,8 @@ func NewPanelDdbWriteUsage(table MetadataCloudAwsDynamodbTable) PanelFactory {
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("Provisioned", "dark-red", ""),
-					NewColorPropertyOverride("Consumed", "super-light-blue", ""),
+					NewColorPropertyOverride("Provisioned", settings.theme.Color(ThemeRoleLimits), ""),
+					NewColorPropertyOverride("Consumed", settings.theme.Color(ThemeRoleTraffic), ""),
 				},
 			},
 			GridPos: settings.gridPos,
@@ -246,11 +248,13 @@ func NewPanelDdbWriteThrottles(table MetadataCloudAwsDynamodbTable) PanelFactory
 			FieldConfig: PanelFieldConfig{
 				Defaults: PanelFieldConfigDefaults{
 					Custom: PanelFieldConfigDefaultsCustom{
-						AxisPlacement: "right",
-						LineWidth:     2,
-						SpanNulls:     true,
+						AxisPlacement:   "right",
+						LineWidth:       2,
+						SpanNulls:       true,
+						ThresholdsStyle: newPanelThresholdsStyle(settings, PanelKindDdbThrottles),
 					},
-					Min: "0",
+					Min:        "0",
+					Thresholds: newPanelThresholdLines(settings, PanelKindDdbThrottles),
 				},
 				Overrides: []PanelFieldConfigOverride{},
 			},
@@ -20,7 +20,7 @@ func TestTaskDeploymentPanelUsesCorrectMetric(t *testing.T) {
 	}
 
 	// Create a dashboard builder and generate the task deployment panel
-	db := builder.NewDashboardBuilder(resourceNames, "kubernetes")
+	db := newDashboardBuilder(t, resourceNames, "kubernetes")
 	db.AddPanel(builder.NewPanelTaskDeployment)
 	dashboard := db.Build("test dashboard")
 
@@ -89,11 +89,11 @@ func TestTaskDeploymentPanelAvoidsCrossTalk(t *testing.T) {
 	}
 
 	// Generate panels for both
-	gatewayDb := builder.NewDashboardBuilder(gatewayResourceNames, "kubernetes")
+	gatewayDb := newDashboardBuilder(t, gatewayResourceNames, "kubernetes")
 	gatewayDb.AddPanel(builder.NewPanelTaskDeployment)
 	gatewayDashboard := gatewayDb.Build("gateway dashboard")
 
-	gatewayAbcDb := builder.NewDashboardBuilder(gatewayAbcResourceNames, "kubernetes")
+	gatewayAbcDb := newDashboardBuilder(t, gatewayAbcResourceNames, "kubernetes")
 	gatewayAbcDb.AddPanel(builder.NewPanelTaskDeployment)
 	gatewayAbcDashboard := gatewayAbcDb.Build("gateway-abc dashboard")
 
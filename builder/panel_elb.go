@@ -9,7 +9,7 @@ func NewPanelElbRequestCount(targetGroupIndex int) PanelFactory {
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("Requests", "semi-dark-blue", ""),
+					NewColorPropertyOverride("Requests", settings.theme.Color(ThemeRoleRequests), ""),
 				},
 			},
 			GridPos: settings.gridPos,
@@ -47,27 +47,25 @@ func NewPanelElbResponseTime(targetGroupIndex int) PanelFactory {
 					Unit: "s",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("Requests", "semi-dark-blue", ""),
+					NewColorPropertyOverride("Requests", settings.theme.Color(ThemeRoleRequests), ""),
 				},
 			},
 			GridPos: settings.gridPos,
-			Targets: []any{
-				PanelTargetCloudWatch{
-					Alias: "Response Time",
-					Dimensions: map[string]string{
-						"TargetGroup":  settings.resourceNames.TargetGroups[targetGroupIndex].TargetGroup,
-						"LoadBalancer": settings.resourceNames.TargetGroups[targetGroupIndex].LoadBalancer,
-					},
-					MatchExact: true,
-					MetricName: "TargetResponseTime",
-					Namespace:  "AWS/ApplicationELB",
-					RefId:      "A",
-					Region:     "default",
-					Statistics: []string{
-						"Average",
-					},
-				},
-			},
+			Targets: newPercentileTargetsCloudWatch(settings, PanelTargetCloudWatch{
+				Alias: "Response Time",
+				Dimensions: map[string]string{
+					"TargetGroup":  settings.resourceNames.TargetGroups[targetGroupIndex].TargetGroup,
+					"LoadBalancer": settings.resourceNames.TargetGroups[targetGroupIndex].LoadBalancer,
+				},
+				MatchExact: true,
+				MetricName: "TargetResponseTime",
+				Namespace:  "AWS/ApplicationELB",
+				RefId:      "A",
+				Region:     "default",
+				Statistics: []string{
+					"Average",
+				},
+			}),
 			Options: &PanelOptionsCloudWatch{},
 			Title:   "Response Time",
 			Type:    "timeseries",
@@ -87,10 +85,10 @@ func NewPanelElbHttpStatus(targetGroupIndex int) PanelFactory {
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("HTTP 2XX", "semi-dark-green", ""),
-					NewColorPropertyOverride("HTTP 3XX", "semi-dark-yellow", ""),
-					NewColorPropertyOverride("HTTP 4XX", "semi-dark-orange", ""),
-					NewColorPropertyOverride("HTTP 5XX", "dark-red", ""),
+					NewColorPropertyOverride("HTTP 2XX", settings.theme.Color(ThemeRoleSuccess), ""),
+					NewColorPropertyOverride("HTTP 3XX", settings.theme.Color(ThemeRoleRedirect), ""),
+					NewColorPropertyOverride("HTTP 4XX", settings.theme.Color(ThemeRoleClientError), ""),
+					NewColorPropertyOverride("HTTP 5XX", settings.theme.Color(ThemeRoleError), ""),
 				},
 			},
 			GridPos: settings.gridPos,
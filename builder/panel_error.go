@@ -11,7 +11,7 @@ func NewPanelError(settings PanelSettings) Panel {
 				},
 			},
 			Overrides: []PanelFieldConfigOverride{
-				NewColorPropertyOverride("Errors", "dark-red", ""),
+				NewColorPropertyOverride("Errors", settings.theme.Color(ThemeRoleError), ""),
 			},
 		},
 		GridPos: settings.gridPos,
@@ -45,7 +45,7 @@ func NewPanelWarn(settings PanelSettings) Panel {
 				},
 			},
 			Overrides: []PanelFieldConfigOverride{
-				NewColorPropertyOverride("Warnings", "dark-yellow", ""),
+				NewColorPropertyOverride("Warnings", settings.theme.Color(ThemeRoleWarning), ""),
 			},
 		},
 		GridPos: settings.gridPos,
@@ -9,7 +9,7 @@ func NewPanelHttpServerRequestCount(serverName string, handler MetadataHttpServe
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("Requests", "semi-dark-blue", ""),
+					NewColorPropertyOverride("Requests", settings.theme.Color(ThemeRoleRequests), ""),
 				},
 			},
 			GridPos: settings.gridPos,
@@ -44,32 +44,34 @@ func NewPanelHttpServerResponseTime(serverName string, handler MetadataHttpServe
 			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
 			FieldConfig: PanelFieldConfig{
 				Defaults: PanelFieldConfigDefaults{
-					Min:  "0",
-					Unit: "ms",
+					Custom: PanelFieldConfigDefaultsCustom{
+						ThresholdsStyle: newPanelThresholdsStyle(settings, PanelKindHttpResponseTime),
+					},
+					Min:        "0",
+					Thresholds: newPanelThresholdLines(settings, PanelKindHttpResponseTime),
+					Unit:       "ms",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("Requests", "semi-dark-blue", ""),
+					NewColorPropertyOverride("Requests", settings.theme.Color(ThemeRoleRequests), ""),
 				},
 			},
 			GridPos: settings.gridPos,
-			Targets: []any{
-				PanelTargetCloudWatch{
-					Alias: "Response Time",
-					Dimensions: map[string]string{
-						"Method":     handler.Method,
-						"Path":       handler.Path,
-						"ServerName": serverName,
-					},
-					MatchExact: true,
-					MetricName: "HttpRequestResponseTimePerRoute",
-					Namespace:  settings.resourceNames.CloudwatchNamespace,
-					RefId:      "A",
-					Region:     "default",
-					Statistics: []string{
-						"Average",
-					},
+			Targets: newPercentileTargetsCloudWatch(settings, PanelTargetCloudWatch{
+				Alias: "Response Time",
+				Dimensions: map[string]string{
+					"Method":     handler.Method,
+					"Path":       handler.Path,
+					"ServerName": serverName,
 				},
-			},
+				MatchExact: true,
+				MetricName: "HttpRequestResponseTimePerRoute",
+				Namespace:  settings.resourceNames.CloudwatchNamespace,
+				RefId:      "A",
+				Region:     "default",
+				Statistics: []string{
+					"Average",
+				},
+			}),
 			Options: &PanelOptionsCloudWatch{},
 			Title:   "Response Time",
 			Type:    "timeseries",
@@ -86,10 +88,10 @@ func NewPanelHttpServerHttpStatus(serverName string, handler MetadataHttpServerH
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("HTTP 2XX", "semi-dark-green", ""),
-					NewColorPropertyOverride("HTTP 3XX", "semi-dark-yellow", ""),
-					NewColorPropertyOverride("HTTP 4XX", "semi-dark-orange", ""),
-					NewColorPropertyOverride("HTTP 5XX", "dark-red", ""),
+					NewColorPropertyOverride("HTTP 2XX", settings.theme.Color(ThemeRoleSuccess), ""),
+					NewColorPropertyOverride("HTTP 3XX", settings.theme.Color(ThemeRoleRedirect), ""),
+					NewColorPropertyOverride("HTTP 4XX", settings.theme.Color(ThemeRoleClientError), ""),
+					NewColorPropertyOverride("HTTP 5XX", settings.theme.Color(ThemeRoleError), ""),
 				},
 			},
 			GridPos: settings.gridPos,
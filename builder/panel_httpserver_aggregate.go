@@ -0,0 +1,267 @@
+package builder
+
+import "fmt"
+
+// DefaultHttpServerTopRoutes is the number of routes listed in the tables of the busiest and slowest routes
+const DefaultHttpServerTopRoutes = 10
+
+type HttpServerAggregateSettings struct {
+	// TopRoutes is the number of routes listed in the tables of the busiest and slowest routes
+	TopRoutes int
+	// RouteSections keeps the sections per route next to the aggregate, limited to the routes matching RoutePaths
+	RouteSections bool
+	RoutePaths    NameFilterSettings
+}
+
+// HttpServerAggregate renders a single section per http server with the totals over all of its routes
+type HttpServerAggregate struct {
+	topRoutes     int
+	routeSections bool
+	routePaths    nameFilter
+}
+
+func NewHttpServerAggregate(settings HttpServerAggregateSettings) (*HttpServerAggregate, error) {
+	if settings.TopRoutes < 0 {
+		return nil, fmt.Errorf("the number of top routes can not be negative, got %d", settings.TopRoutes)
+	}
+
+	routePaths, err := newNameFilter("route path", settings.RoutePaths)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate := &HttpServerAggregate{
+		topRoutes:     settings.TopRoutes,
+		routeSections: settings.RouteSections,
+		routePaths:    routePaths,
+	}
+
+	if aggregate.topRoutes == 0 {
+		aggregate.topRoutes = DefaultHttpServerTopRoutes
+	}
+
+	return aggregate, nil
+}
+
+func (a *HttpServerAggregate) includesRouteSection(handler MetadataHttpServerHandler) bool {
+	return a.routeSections && a.routePaths.matches(handler.Path)
+}
+
+// cloudwatchSearchPeriod is the period in seconds of the metrics found by search expressions
+const cloudwatchSearchPeriod = 60
+
+// httpServerSearch finds the metric of every route of the server, regardless of the path and method. Without a server
+// name, the routes of all servers are found.
+func httpServerSearch(settings PanelSettings, serverName string, metricName string, statistic string) string {
+	conditions := fmt.Sprintf(`MetricName="%s"`, metricName)
+	if serverName != "" {
+		conditions += fmt.Sprintf(` ServerName="%s"`, serverName)
+	}
+
+	return fmt.Sprintf(
+		`SEARCH('{"%s",Method,Path,ServerName} %s', '%s', %d)`,
+		settings.resourceNames.CloudwatchNamespace,
+		conditions,
+		statistic,
+		cloudwatchSearchPeriod,
+	)
+}
+
+func newCloudWatchExpressionTarget(id string, alias string, expression string, refId string) PanelTargetCloudWatch {
+	return PanelTargetCloudWatch{
+		Alias:      alias,
+		Dimensions: map[string]string{},
+		Expression: expression,
+		Id:         id,
+		RefId:      refId,
+		Region:     "default",
+		Statistics: []string{},
+	}
+}
+
+func NewPanelHttpServerTotalRequestCount(server MetadataHttpServer) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		return Panel{
+			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Min: "0",
+				},
+				Overrides: []PanelFieldConfigOverride{
+					NewColorPropertyOverride("Requests", settings.theme.Color(ThemeRoleRequests), ""),
+				},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				newCloudWatchExpressionTarget(
+					"requests",
+					"Requests",
+					fmt.Sprintf("SUM(%s)", httpServerSearch(settings, server.Name, "HttpRequestCountPerRoute", "Sum")),
+					"A",
+				),
+			},
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "Total Request Count",
+			Type:    "timeseries",
+		}
+	}
+}
+
+// NewPanelHttpServerTotalResponseTime shows the average response time over all routes, weighted by their requests
+func NewPanelHttpServerTotalResponseTime(server MetadataHttpServer) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		return Panel{
+			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Min:  "0",
+					Unit: "ms",
+				},
+				Overrides: []PanelFieldConfigOverride{},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				newCloudWatchExpressionTarget(
+					"response_time",
+					"Response Time",
+					fmt.Sprintf(
+						"SUM(%s)/SUM(%s)",
+						httpServerSearch(settings, server.Name, "HttpRequestResponseTimePerRoute", "Sum"),
+						httpServerSearch(settings, server.Name, "HttpRequestResponseTimePerRoute", "SampleCount"),
+					),
+					"A",
+				),
+			},
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "Average Response Time",
+			Type:    "timeseries",
+		}
+	}
+}
+
+func NewPanelHttpServerTotalHttpStatus(server MetadataHttpServer) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		targets := make([]any, 0, 4)
+
+		for i, status := range []string{"2XX", "3XX", "4XX", "5XX"} {
+			targets = append(targets, newCloudWatchExpressionTarget(
+				fmt.Sprintf("status_%s", status),
+				fmt.Sprintf("HTTP %s", status),
+				fmt.Sprintf("SUM(%s)", httpServerSearch(settings, server.Name, fmt.Sprintf("HttpStatus%sPerRoute", status), "Sum")),
+				string(rune('A'+i)),
+			))
+		}
+
+		return Panel{
+			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Min: "0",
+				},
+				Overrides: []PanelFieldConfigOverride{
+					NewColorPropertyOverride("HTTP 2XX", settings.theme.Color(ThemeRoleSuccess), ""),
+					NewColorPropertyOverride("HTTP 3XX", settings.theme.Color(ThemeRoleRedirect), ""),
+					NewColorPropertyOverride("HTTP 4XX", settings.theme.Color(ThemeRoleClientError), ""),
+					NewColorPropertyOverride("HTTP 5XX", settings.theme.Color(ThemeRoleError), ""),
+				},
+			},
+			GridPos: settings.gridPos,
+			Targets: targets,
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "Total HTTP Status Overview",
+			Type:    "timeseries",
+		}
+	}
+}
+
+// httpServerTopRoutes describes a table of the routes with the highest value of a metric
+type httpServerTopRoutes struct {
+	metricName   string
+	statistic    string
+	sortFunction string
+	// reducer is the grafana reducer of the series to a single value, which is named reducerField in the table
+	reducer      string
+	reducerField string
+	column       string
+	unit         string
+	title        string
+}
+
+// NewPanelHttpServerBusiestRoutes lists the topN routes with the most requests in the selected time range
+func NewPanelHttpServerBusiestRoutes(server MetadataHttpServer, topN int) PanelFactory {
+	return newPanelHttpServerTopRoutes(server, topN, httpServerTopRoutes{
+		metricName:   "HttpRequestCountPerRoute",
+		statistic:    "Sum",
+		sortFunction: "SUM",
+		reducer:      "sum",
+		reducerField: "Total",
+		column:       "Requests",
+		unit:         "short",
+		title:        "Busiest Routes",
+	})
+}
+
+// NewPanelHttpServerSlowestRoutes lists the topN routes with the highest average response time in the selected time range
+func NewPanelHttpServerSlowestRoutes(server MetadataHttpServer, topN int) PanelFactory {
+	return newPanelHttpServerTopRoutes(server, topN, httpServerTopRoutes{
+		metricName:   "HttpRequestResponseTimePerRoute",
+		statistic:    "Average",
+		sortFunction: "AVG",
+		reducer:      "mean",
+		reducerField: "Mean",
+		column:       "Response Time",
+		unit:         "ms",
+		title:        "Slowest Routes",
+	})
+}
+
+func newPanelHttpServerTopRoutes(server MetadataHttpServer, topN int, top httpServerTopRoutes) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		return Panel{
+			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Unit: top.unit,
+				},
+				Overrides: []PanelFieldConfigOverride{},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				newCloudWatchExpressionTarget(
+					"routes",
+					"{{Method}} {{Path}}",
+					fmt.Sprintf("SORT(%s, %s, DESC, %d)", httpServerSearch(settings, server.Name, top.metricName, top.statistic), top.sortFunction, topN),
+					"A",
+				),
+			},
+			Transformations: []PanelTransformation{
+				{
+					Id: "reduce",
+					Options: map[string]any{
+						"reducers": []string{top.reducer},
+					},
+				},
+				{
+					Id: "organize",
+					Options: map[string]any{
+						"renameByName": map[string]string{
+							"Field":          "Route",
+							top.reducerField: top.column,
+						},
+					},
+				},
+			},
+			Options: &PanelOptionsTable{
+				ShowHeader: true,
+				SortBy: []PanelOptionsTableSortBy{
+					{
+						Desc:        true,
+						DisplayName: top.column,
+					},
+				},
+			},
+			Title: top.title,
+			Type:  "table",
+		}
+	}
+}
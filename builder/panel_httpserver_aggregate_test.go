@@ -0,0 +1,97 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func provideHttpServer() builder.MetadataHttpServer {
+	return builder.MetadataHttpServer{
+		Name: "default",
+		Handlers: builder.MetadataHttpServerHandlers{
+			{Method: "GET", Path: "/v1/orders"},
+			{Method: "POST", Path: "/v1/orders"},
+			{Method: "GET", Path: "/v1/users"},
+		},
+	}
+}
+
+func TestHttpServerAggregate(t *testing.T) {
+	aggregate, err := builder.NewHttpServerAggregate(builder.HttpServerAggregateSettings{
+		TopRoutes: 5,
+	})
+	assert.NoError(t, err)
+
+	resourceNames := &builder.ResourceNames{
+		CloudwatchNamespace:             "gosoline/test/monitoring/grp/dashboard",
+		GrafanaCloudWatchDatasourceName: "cw",
+	}
+
+	db := newDashboardBuilder(t, resourceNames, "ecs", builder.WithHttpServerAggregate(aggregate))
+	db.AddHttpServer(provideHttpServer())
+
+	dashboard := db.Build("")
+
+	titles := make([]string, len(dashboard.Panels))
+	for i, panel := range dashboard.Panels {
+		titles[i] = panel.Title
+	}
+
+	assert.Equal(t, []string{
+		"HttpServer default: All Routes",
+		"Total Request Count",
+		"Average Response Time",
+		"Total HTTP Status Overview",
+		"Busiest Routes",
+		"Slowest Routes",
+	}, titles)
+
+	requests := dashboard.Panels[1].Targets[0].(builder.PanelTargetCloudWatch)
+	assert.Equal(t, `SUM(SEARCH('{"gosoline/test/monitoring/grp/dashboard",Method,Path,ServerName} MetricName="HttpRequestCountPerRoute" ServerName="default"', 'Sum', 60))`, requests.Expression)
+
+	busiest := dashboard.Panels[4]
+	assert.Equal(t, "table", busiest.Type)
+	assert.Equal(t, `SORT(SEARCH('{"gosoline/test/monitoring/grp/dashboard",Method,Path,ServerName} MetricName="HttpRequestCountPerRoute" ServerName="default"', 'Sum', 60), SUM, DESC, 5)`, busiest.Targets[0].(builder.PanelTargetCloudWatch).Expression)
+	assert.Equal(t, "reduce", busiest.Transformations[0].Id)
+
+	slowest := dashboard.Panels[5].Targets[0].(builder.PanelTargetCloudWatch)
+	assert.Contains(t, slowest.Expression, `MetricName="HttpRequestResponseTimePerRoute"`)
+	assert.Contains(t, slowest.Expression, ", AVG, DESC, 5)")
+}
+
+func TestHttpServerAggregateRouteSections(t *testing.T) {
+	aggregate, err := builder.NewHttpServerAggregate(builder.HttpServerAggregateSettings{
+		RouteSections: true,
+		RoutePaths: builder.NameFilterSettings{
+			Include: []string{"^/v1/orders$"},
+		},
+	})
+	assert.NoError(t, err)
+
+	db := newDashboardBuilder(t, &builder.ResourceNames{}, "ecs", builder.WithHttpServerAggregate(aggregate))
+	db.AddHttpServer(provideHttpServer())
+
+	dashboard := db.Build("")
+
+	rows := make([]string, 0)
+	for _, panel := range dashboard.Panels {
+		if panel.Type == "row" {
+			rows = append(rows, panel.Title)
+		}
+	}
+
+	assert.Equal(t, []string{
+		"HttpServer default: All Routes",
+		"HttpServer default: GET /v1/orders",
+		"HttpServer default: POST /v1/orders",
+	}, rows)
+
+	// the default number of top routes is used if none is configured
+	busiest := dashboard.Panels[4].Targets[0].(builder.PanelTargetCloudWatch)
+	assert.Contains(t, busiest.Expression, ", SUM, DESC, 10)")
+
+	_, err = builder.NewHttpServerAggregate(builder.HttpServerAggregateSettings{TopRoutes: -1})
+	assert.EqualError(t, err, "the number of top routes can not be negative, got -1")
+}
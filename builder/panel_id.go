@@ -0,0 +1,55 @@
+package builder
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// panelIds hands out the ids of the panels. An id is derived from the section the panel belongs to and the panel
+// itself, so adding or removing a section doesn't renumber the panels of the other sections.
+type panelIds struct {
+	section     string
+	occurrences map[string]int
+	used        map[int]bool
+}
+
+func newPanelIds() *panelIds {
+	return &panelIds{
+		occurrences: make(map[string]int),
+		used:        make(map[int]bool),
+	}
+}
+
+func (p *panelIds) assign(panels []Panel) {
+	for i := range panels {
+		if panels[i].Type == "row" {
+			p.section = panels[i].Title
+		}
+
+		panels[i].Id = p.next(panels[i])
+
+		p.assign(panels[i].Panels)
+	}
+}
+
+func (p *panelIds) next(panel Panel) int {
+	// panels with the same title in the same section, e.g. the cpu usage of several containers, are told apart by their position
+	key := fmt.Sprintf("%s\x00%s\x00%s", p.section, panel.Type, panel.Title)
+	occurrence := p.occurrences[key]
+	p.occurrences[key]++
+
+	hash := fnv.New32a()
+	_, _ = fmt.Fprintf(hash, "%s\x00%d", key, occurrence)
+
+	// grafana stores the ids as javascript numbers, keep them positive
+	id := int(hash.Sum32() & 0x7fffffff)
+
+	// 0 means "no id" to grafana, collisions are resolved in the order of the panels which is stable as well
+	for id == 0 || p.used[id] {
+		id++
+	}
+
+	p.used[id] = true
+
+	return id
+}
@@ -0,0 +1,62 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func collectPanelIds(panels []builder.Panel, ids map[string]int) {
+	for _, panel := range panels {
+		ids[panel.Title] = panel.Id
+		collectPanelIds(panel.Panels, ids)
+	}
+}
+
+func TestPanelIdsAreStable(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		Containers: []string{"app"},
+	}
+	queue := builder.MetadataCloudAwsSqsQueue{QueueNameFull: "queue"}
+	topic := builder.MetadataCloudAwsSnsTopic{TopicName: "topic"}
+
+	db := newDashboardBuilder(t, resourceNames, "ecs")
+	db.AddCloudAwsSqsQueue(queue)
+	db.AddCloudAwsSnsTopic(topic)
+	before := db.Build("")
+
+	db = newDashboardBuilder(t, resourceNames, "ecs", builder.WithCollapsedSections(builder.DashboardSectionSns))
+	db.AddServiceAndTask()
+	db.AddCloudAwsSqsQueue(queue)
+	db.AddCloudAwsSnsTopic(topic)
+	after := db.Build("")
+
+	beforeIds := make(map[string]int)
+	afterIds := make(map[string]int)
+	collectPanelIds(before.Panels, beforeIds)
+	collectPanelIds(after.Panels, afterIds)
+
+	// adding a section or collapsing a row doesn't change the ids of the other panels
+	for title, id := range beforeIds {
+		assert.Equal(t, id, afterIds[title], title)
+	}
+
+	unique := make(map[int]bool)
+	for title, id := range afterIds {
+		assert.NotZero(t, id, title)
+		assert.False(t, unique[id], "duplicate id for %s", title)
+		unique[id] = true
+	}
+}
+
+func TestPanelIdsOfPanelsWithTheSameTitle(t *testing.T) {
+	db := newDashboardBuilder(t, &builder.ResourceNames{}, "ecs")
+	db.AddCloudAwsSqsQueue(builder.MetadataCloudAwsSqsQueue{QueueNameFull: "a"})
+	db.AddCloudAwsSqsQueue(builder.MetadataCloudAwsSqsQueue{QueueNameFull: "b"})
+	dashboard := db.Build("")
+
+	assert.Equal(t, "Traffic", dashboard.Panels[2].Title)
+	assert.Equal(t, "Traffic", dashboard.Panels[6].Title)
+	assert.NotEqual(t, dashboard.Panels[2].Id, dashboard.Panels[6].Id)
+}
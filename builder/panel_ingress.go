@@ -0,0 +1,150 @@
+package builder
+
+import "fmt"
+
+// prometheusIngressMetrics describes the prometheus metrics of an ingress which are exposing the requests and their
+// duration per destination service
+type prometheusIngressMetrics struct {
+	labelFilter   func(resourceNames *ResourceNames) string
+	requests      string
+	statusLabel   string
+	durationSum   string
+	durationCount string
+	// durationBucket is the histogram the percentiles of the duration are computed from
+	durationBucket string
+	durationUnit   string
+}
+
+func newPanelIngressRequestCount(metrics prometheusIngressMetrics) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		labelFilter := metrics.labelFilter(settings.resourceNames)
+
+		return Panel{
+			Datasource: datasourcePrometheus,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Min: "0",
+				},
+				Overrides: []PanelFieldConfigOverride{
+					NewColorPropertyOverride("Requests", settings.theme.Color(ThemeRoleRequests), ""),
+				},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				PanelTargetPrometheus{
+					Exemplar:     true,
+					Expression:   fmt.Sprintf(`sum(irate(%s{%s}[1m])) * 60`, metrics.requests, labelFilter),
+					LegendFormat: "Requests",
+					RefId:        "Requests",
+				},
+			},
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "Request Count",
+			Type:    "timeseries",
+		}
+	}
+}
+
+func newPanelIngressResponseTime(metrics prometheusIngressMetrics) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		labelFilter := metrics.labelFilter(settings.resourceNames)
+
+		return Panel{
+			Datasource: datasourcePrometheus,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Min:  "0",
+					Unit: metrics.durationUnit,
+				},
+				Overrides: []PanelFieldConfigOverride{
+					NewColorPropertyOverride("Response Time", settings.theme.Color(ThemeRoleLatency), ""),
+				},
+			},
+			GridPos: settings.gridPos,
+			Targets: newPercentileTargetsPrometheus(settings, PanelTargetPrometheus{
+				Exemplar:     true,
+				Expression:   fmt.Sprintf(`sum(irate(%s{%s}[$__rate_interval])) / sum(irate(%s{%s}[$__rate_interval]))`, metrics.durationSum, labelFilter, metrics.durationCount, labelFilter),
+				LegendFormat: "Response Time",
+				RefId:        "Requests",
+			}, metrics.durationBucket, labelFilter),
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "Response Time",
+			Type:    "timeseries",
+		}
+	}
+}
+
+func newPanelIngressHttpStatus(metrics prometheusIngressMetrics) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		labelFilter := metrics.labelFilter(settings.resourceNames)
+		statusClasses := []struct {
+			prefix string
+			name   string
+			role   string
+			refId  string
+		}{
+			{prefix: "2", name: "HTTP 2XX", role: ThemeRoleSuccess, refId: "A"},
+			{prefix: "3", name: "HTTP 3XX", role: ThemeRoleRedirect, refId: "B"},
+			{prefix: "4", name: "HTTP 4XX", role: ThemeRoleClientError, refId: "C"},
+			{prefix: "5", name: "HTTP 5XX", role: ThemeRoleError, refId: "D"},
+		}
+
+		overrides := make([]PanelFieldConfigOverride, len(statusClasses))
+		targets := make([]any, len(statusClasses))
+
+		for i, statusClass := range statusClasses {
+			overrides[i] = NewColorPropertyOverride(statusClass.name, settings.theme.Color(statusClass.role), "")
+			targets[i] = PanelTargetPrometheus{
+				Exemplar:     true,
+				Expression:   fmt.Sprintf(`sum(irate(%s{%s=~"%s.*",%s}[1m])) * 60 or vector(0)`, metrics.requests, metrics.statusLabel, statusClass.prefix, labelFilter),
+				LegendFormat: statusClass.name,
+				RefId:        statusClass.refId,
+			}
+		}
+
+		return Panel{
+			Datasource: datasourcePrometheus,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Min: "0",
+				},
+				Overrides: overrides,
+			},
+			GridPos: settings.gridPos,
+			Targets: targets,
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "HTTP Status Overview",
+			Type:    "timeseries",
+		}
+	}
+}
+
+func newPanelIngressRequestCountPerTarget(metrics prometheusIngressMetrics) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		labelFilter := metrics.labelFilter(settings.resourceNames)
+		labelFilterPod := getKubernetesPodLabelFilter(settings.resourceNames)
+		selectorJoin := getKubernetesPodSelectorJoin(settings.resourceNames)
+
+		return Panel{
+			Datasource: datasourcePrometheus,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Min: "0",
+				},
+				Overrides: []PanelFieldConfigOverride{},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				PanelTargetPrometheus{
+					Exemplar:     true,
+					Expression:   fmt.Sprintf(`sum(irate(%s{%s}[1m])) by () * 60/count(kube_pod_status_ready{condition="true",%s}%s)`, metrics.requests, labelFilter, labelFilterPod, selectorJoin),
+					LegendFormat: "Requests",
+					RefId:        "A",
+				},
+			},
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "Requests Per Healthy Target",
+			Type:    "timeseries",
+		}
+	}
+}
@@ -9,11 +9,13 @@ func NewPanelKinesisKinsumerMillisecondsBehind(stream MetadataCloudAwsKinesisKin
 			FieldConfig: PanelFieldConfig{
 				Defaults: PanelFieldConfigDefaults{
 					Custom: PanelFieldConfigDefaultsCustom{
-						LineWidth:     2,
-						AxisPlacement: "right",
+						LineWidth:       2,
+						AxisPlacement:   "right",
+						ThresholdsStyle: newPanelThresholdsStyle(settings, PanelKindKinsumerLag),
 					},
-					Min:  "0",
-					Unit: "ms",
+					Min:        "0",
+					Thresholds: newPanelThresholdLines(settings, PanelKindKinsumerLag),
+					Unit:       "ms",
 				},
 				Overrides: []PanelFieldConfigOverride{},
 			},
@@ -57,8 +59,8 @@ func NewPanelKinesisKinsumerMessageCounts(stream MetadataCloudAwsKinesisKinsumer
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("ReadRecords", "semi-dark-blue", ""),
-					NewColorPropertyOverride("FailedRecords", "dark-red", ""),
+					NewColorPropertyOverride("ReadRecords", settings.theme.Color(ThemeRoleRequests), ""),
+					NewColorPropertyOverride("FailedRecords", settings.theme.Color(ThemeRoleError), ""),
 				},
 			},
 			GridPos: settings.gridPos,
@@ -114,7 +116,7 @@ func NewPanelKinesisKinsumerReadOperations(stream MetadataCloudAwsKinesisKinsume
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("ReadCount Limit", "dark-red", ""),
+					NewColorPropertyOverride("ReadCount Limit", settings.theme.Color(ThemeRoleLimits), ""),
 				},
 			},
 			GridPos: settings.gridPos,
@@ -336,8 +338,8 @@ func NewPanelKinesisStreamGetRecordsBytes(stream KinesisStreamAware) PanelFactor
 					Unit: "decbytes",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("Limit", "dark-red", ""),
-					NewColorPropertyOverride("GetRecordsBytes", "super-light-blue", ""),
+					NewColorPropertyOverride("Limit", settings.theme.Color(ThemeRoleLimits), ""),
+					NewColorPropertyOverride("GetRecordsBytes", settings.theme.Color(ThemeRoleTraffic), ""),
 				},
 			},
 			GridPos: settings.gridPos,
@@ -391,8 +393,8 @@ func NewPanelKinesisStreamIncomingDataBytes(stream KinesisStreamAware) PanelFact
 					Unit: "decbytes",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("Limit", "dark-red", ""),
-					NewColorPropertyOverride("IncomingBytes", "super-light-blue", ""),
+					NewColorPropertyOverride("Limit", settings.theme.Color(ThemeRoleLimits), ""),
+					NewColorPropertyOverride("IncomingBytes", settings.theme.Color(ThemeRoleTraffic), ""),
 				},
 			},
 			GridPos: settings.gridPos,
@@ -443,8 +445,8 @@ func NewPanelKinesisStreamIncomingDataCount(stream KinesisStreamAware) PanelFact
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("Limit", "dark-red", ""),
-					NewColorPropertyOverride("IncomingRecords", "super-light-blue", ""),
+					NewColorPropertyOverride("Limit", settings.theme.Color(ThemeRoleLimits), ""),
+					NewColorPropertyOverride("IncomingRecords", settings.theme.Color(ThemeRoleTraffic), ""),
 				},
 			},
 			GridPos: settings.gridPos,
@@ -496,8 +498,8 @@ func NewPanelKinesisRecordWriterPutRecordsCount(stream MetadataCloudAwsKinesisRe
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("PutRecords", "semi-dark-blue", ""),
-					NewColorPropertyOverride("PutRecordsFailure", "dark-red", ""),
+					NewColorPropertyOverride("PutRecords", settings.theme.Color(ThemeRoleRequests), ""),
+					NewColorPropertyOverride("PutRecordsFailure", settings.theme.Color(ThemeRoleError), ""),
 				},
 			},
 			GridPos: settings.gridPos,
@@ -1,8 +1,10 @@
 package builder
 
+const logsPanelHeight = 16
+
 func NewPanelLogs(settings PanelSettings) Panel {
-	settings.gridPos.W = 24
-	settings.gridPos.H = 16
+	settings.gridPos.W = DashboadWidth
+	settings.gridPos.H = logsPanelHeight
 
 	return Panel{
 		Datasource: settings.resourceNames.GrafanaElasticsearchDatasourceName,
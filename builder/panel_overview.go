@@ -0,0 +1,281 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+const overviewPanelHeight = 4
+
+// overviewPanel sizes the panel to share the overview row with the other headline panels
+func overviewPanel(factory PanelFactory, width int) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		settings.gridPos.W = width
+		settings.gridPos.H = overviewPanelHeight
+
+		return factory(settings)
+	}
+}
+
+// searchAlternatives matches any of the values of the dimension in a cloudwatch search expression
+func searchAlternatives(dimension string, values []string) string {
+	alternatives := make([]string, len(values))
+	for i, value := range values {
+		alternatives[i] = fmt.Sprintf(`%s="%s"`, dimension, value)
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(alternatives, " OR "))
+}
+
+// newOverviewThresholds colors the headline panels without a panel kind in the ok color of the theme
+func newOverviewThresholds(settings PanelSettings) PanelFieldConfigDefaultsThresholds {
+	return PanelFieldConfigDefaultsThresholds{
+		Mode: "absolute",
+		Steps: []PanelFieldConfigDefaultsThresholdsStep{
+			{Color: settings.theme.Color(ThemeRoleThresholdOk)},
+		},
+	}
+}
+
+func newPanelOptionsStat(calc string) *PanelOptionsStat {
+	return &PanelOptionsStat{
+		ColorMode:   "value",
+		GraphMode:   "area",
+		JustifyMode: "auto",
+		Orientation: "auto",
+		ReduceOptions: PanelOptionsReduce{
+			Calcs: []string{calc},
+		},
+		TextMode: "auto",
+	}
+}
+
+func NewPanelOverviewErrors(settings PanelSettings) Panel {
+	return Panel{
+		Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+		FieldConfig: PanelFieldConfig{
+			Defaults: PanelFieldConfigDefaults{
+				Thresholds: newPanelThresholds(settings, PanelKindErrors),
+			},
+			Overrides: []PanelFieldConfigOverride{},
+		},
+		GridPos: settings.gridPos,
+		Targets: []any{
+			PanelTargetCloudWatch{
+				Alias:      "Errors",
+				Dimensions: map[string]string{},
+				MatchExact: false,
+				MetricName: "error",
+				Namespace:  settings.resourceNames.CloudwatchNamespace,
+				RefId:      "A",
+				Region:     "default",
+				Statistics: []string{
+					"Sum",
+				},
+			},
+		},
+		Options: newPanelOptionsStat("sum"),
+		Title:   "Errors",
+		Type:    "stat",
+	}
+}
+
+// NewPanelOverviewHttp5xxRatio shows the share of the requests of all http servers which failed with a 5xx status
+func NewPanelOverviewHttp5xxRatio(settings PanelSettings) Panel {
+	return Panel{
+		Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+		FieldConfig: PanelFieldConfig{
+			Defaults: PanelFieldConfigDefaults{
+				Max:        "100",
+				Min:        "0",
+				Unit:       "percent",
+				Thresholds: newPanelThresholds(settings, PanelKindHttp5xxRatio),
+			},
+			Overrides: []PanelFieldConfigOverride{},
+		},
+		GridPos: settings.gridPos,
+		Targets: []any{
+			newCloudWatchExpressionTarget(
+				"ratio_5xx",
+				"5xx Ratio",
+				fmt.Sprintf(
+					"100*SUM(%s)/SUM(%s)",
+					httpServerSearch(settings, "", "HttpStatus5XXPerRoute", "Sum"),
+					httpServerSearch(settings, "", "HttpRequestCountPerRoute", "Sum"),
+				),
+				"A",
+			),
+		},
+		Options: &PanelOptionsGauge{
+			Orientation: "auto",
+			ReduceOptions: PanelOptionsReduce{
+				Calcs: []string{"lastNotNull"},
+			},
+			ShowThresholdMarkers: true,
+		},
+		Title: "5xx Ratio",
+		Type:  "gauge",
+	}
+}
+
+// NewPanelOverviewHttpP99 shows the p99 latency of the slowest route of all http servers
+func NewPanelOverviewHttpP99(settings PanelSettings) Panel {
+	return Panel{
+		Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+		FieldConfig: PanelFieldConfig{
+			Defaults: PanelFieldConfigDefaults{
+				Unit:       "ms",
+				Thresholds: newPanelThresholds(settings, PanelKindHttpResponseTime),
+			},
+			Overrides: []PanelFieldConfigOverride{},
+		},
+		GridPos: settings.gridPos,
+		Targets: []any{
+			newCloudWatchExpressionTarget(
+				"latency_p99",
+				"p99 Latency",
+				fmt.Sprintf("MAX(%s)", httpServerSearch(settings, "", "HttpRequestResponseTimePerRoute", "p99")),
+				"A",
+			),
+		},
+		Options: newPanelOptionsStat("lastNotNull"),
+		Title:   "p99 Latency",
+		Type:    "stat",
+	}
+}
+
+// NewPanelOverviewReplicas shows the running replicas of the application next to the desired ones, if they are known
+func NewPanelOverviewReplicas(runningQuery string, desiredQuery string) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		targets := []any{
+			PanelTargetPrometheus{
+				Exemplar:     true,
+				Expression:   runningQuery,
+				LegendFormat: "Running",
+				RefId:        "A",
+			},
+		}
+
+		if desiredQuery != "" {
+			targets = append(targets, PanelTargetPrometheus{
+				Exemplar:     true,
+				Expression:   desiredQuery,
+				LegendFormat: "Desired",
+				RefId:        "B",
+			})
+		}
+
+		return Panel{
+			Datasource: datasourcePrometheus,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Thresholds: newOverviewThresholds(settings),
+				},
+				Overrides: []PanelFieldConfigOverride{},
+			},
+			GridPos: settings.gridPos,
+			Targets: targets,
+			Options: newPanelOptionsStat("lastNotNull"),
+			Title:   "Running Replicas",
+			Type:    "stat",
+		}
+	}
+}
+
+func NewPanelOverviewContainerInsightsTasks(settings PanelSettings) Panel {
+	targets := make([]any, 0, 2)
+
+	for i, metric := range []struct {
+		alias string
+		name  string
+	}{
+		{alias: "Running", name: "RunningTaskCount"},
+		{alias: "Desired", name: "DesiredTaskCount"},
+	} {
+		targets = append(targets, PanelTargetCloudWatch{
+			Alias:      metric.alias,
+			Dimensions: getContainerInsightsDimensions(settings),
+			MatchExact: true,
+			MetricName: metric.name,
+			Namespace:  namespaceContainerInsights,
+			RefId:      string(rune('A' + i)),
+			Region:     "default",
+			Statistics: []string{
+				"Average",
+			},
+		})
+	}
+
+	return Panel{
+		Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+		FieldConfig: PanelFieldConfig{
+			Defaults: PanelFieldConfigDefaults{
+				Thresholds: newOverviewThresholds(settings),
+			},
+			Overrides: []PanelFieldConfigOverride{},
+		},
+		GridPos: settings.gridPos,
+		Targets: targets,
+		Options: newPanelOptionsStat("lastNotNull"),
+		Title:   "Running Tasks",
+		Type:    "stat",
+	}
+}
+
+// NewPanelOverviewKinsumerLag shows the highest MillisecondsBehind of the kinsumers reading the given streams
+func NewPanelOverviewKinsumerLag(streamNames []string) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		search := fmt.Sprintf(
+			`SEARCH('Namespace="%s" MetricName="MillisecondsBehind" %s', 'Maximum', %d)`,
+			settings.resourceNames.CloudwatchNamespace,
+			searchAlternatives("StreamName", streamNames),
+			cloudwatchSearchPeriod,
+		)
+
+		return Panel{
+			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Unit:       "ms",
+					Thresholds: newPanelThresholds(settings, PanelKindKinsumerLag),
+				},
+				Overrides: []PanelFieldConfigOverride{},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				newCloudWatchExpressionTarget("kinsumer_lag", "MillisecondsBehind", fmt.Sprintf("MAX(%s)", search), "A"),
+			},
+			Options: newPanelOptionsStat("lastNotNull"),
+			Title:   "Max Kinsumer Lag",
+			Type:    "stat",
+		}
+	}
+}
+
+// NewPanelOverviewSqsBacklog shows the visible messages of the fullest of the given queues
+func NewPanelOverviewSqsBacklog(queueNames []string) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		search := fmt.Sprintf(
+			`SEARCH('{AWS/SQS,QueueName} MetricName="ApproximateNumberOfMessagesVisible" %s', 'Maximum', %d)`,
+			searchAlternatives("QueueName", queueNames),
+			cloudwatchSearchPeriod,
+		)
+
+		return Panel{
+			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Thresholds: newPanelThresholds(settings, PanelKindSqsMessagesVisible),
+				},
+				Overrides: []PanelFieldConfigOverride{},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				newCloudWatchExpressionTarget("sqs_backlog", "Messages In Queue", fmt.Sprintf("MAX(%s)", search), "A"),
+			},
+			Options: newPanelOptionsStat("lastNotNull"),
+			Title:   "Max SQS Backlog",
+			Type:    "stat",
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package builder_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func provideOverviewMetadata() *builder.MetadataApplication {
+	metadata := &builder.MetadataApplication{
+		HttpServers: builder.MetadataHttpServers{provideHttpServer()},
+	}
+
+	metadata.Cloud.Aws.Kinesis.Kinsumers = builder.MetadataCloudAwsKinesisKinsumers{
+		{StreamNameFull: "prj-env-fam-grp-events"},
+	}
+	metadata.Cloud.Aws.Sqs.Queues = builder.MetadataCloudAwsSqsQueues{
+		{QueueNameFull: "prj-env-fam-grp-app-orders"},
+		{QueueNameFull: "prj-env-fam-grp-app-jobs"},
+	}
+
+	return metadata
+}
+
+func TestDashboardOverview(t *testing.T) {
+	resourceNames := provideIngressResourceNames()
+	resourceNames.CloudwatchNamespace = "prj/env/fam/grp/app"
+
+	db := newDashboardBuilder(t, resourceNames, "kubernetes")
+	db.AddOverview(provideOverviewMetadata())
+	dashboard := db.Build("")
+
+	assert.Len(t, dashboard.Panels, 7)
+	assert.Equal(t, "Overview", dashboard.Panels[0].Title)
+
+	types := make([]string, 0)
+	titles := make([]string, 0)
+
+	for _, panel := range dashboard.Panels[1:] {
+		types = append(types, panel.Type)
+		titles = append(titles, panel.Title)
+
+		// the six stats share a single row
+		assert.Equal(t, builder.PanelGridPos{H: 4, W: 4, X: panel.GridPos.X, Y: 1}, panel.GridPos)
+	}
+
+	assert.Equal(t, []string{"stat", "gauge", "stat", "stat", "stat", "stat"}, types)
+	assert.Equal(t, []string{"Errors", "5xx Ratio", "p99 Latency", "Running Replicas", "Max Kinsumer Lag", "Max SQS Backlog"}, titles)
+
+	replicas := dashboard.Panels[4]
+	assert.Equal(t, `sum(kube_deployment_status_replicas_ready{namespace="prj", deployment="grp-app"})`, replicas.Targets[0].(builder.PanelTargetPrometheus).Expression)
+	assert.Equal(t, `sum(kube_deployment_spec_replicas{namespace="prj", deployment="grp-app"})`, replicas.Targets[1].(builder.PanelTargetPrometheus).Expression)
+
+	ratio := dashboard.Panels[2].Targets[0].(builder.PanelTargetCloudWatch)
+	assert.Equal(t, `100*SUM(SEARCH('{"prj/env/fam/grp/app",Method,Path,ServerName} MetricName="HttpStatus5XXPerRoute"', 'Sum', 60))/SUM(SEARCH('{"prj/env/fam/grp/app",Method,Path,ServerName} MetricName="HttpRequestCountPerRoute"', 'Sum', 60))`, ratio.Expression)
+
+	backlog := dashboard.Panels[6].Targets[0].(builder.PanelTargetCloudWatch)
+	assert.Equal(t, `MAX(SEARCH('{AWS/SQS,QueueName} MetricName="ApproximateNumberOfMessagesVisible" (QueueName="prj-env-fam-grp-app-jobs" OR QueueName="prj-env-fam-grp-app-orders")', 'Maximum', 60))`, backlog.Expression)
+
+	body, err := json.Marshal(dashboard.Panels[1])
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"reduceOptions":{"calcs":["sum"],"fields":"","values":false}`)
+}
+
+func TestDashboardOverviewWithoutResources(t *testing.T) {
+	db := newDashboardBuilder(t, provideLayoutResourceNames(), "ecs_fargate")
+	db.AddOverview(&builder.MetadataApplication{})
+	dashboard := db.Build("")
+
+	assert.Len(t, dashboard.Panels, 3)
+	assert.Equal(t, "Errors", dashboard.Panels[1].Title)
+	assert.Equal(t, "Running Tasks", dashboard.Panels[2].Title)
+	assert.Equal(t, 12, dashboard.Panels[2].GridPos.W)
+
+	filter, err := builder.NewDashboardFilter(builder.DashboardFilterSettings{
+		ExcludeSections: []string{builder.DashboardSectionOverview},
+	})
+	assert.NoError(t, err)
+
+	db = newDashboardBuilder(t, provideLayoutResourceNames(), "ecs", builder.WithFilter(filter))
+	db.AddOverview(provideOverviewMetadata())
+
+	assert.Empty(t, db.Build("").Panels)
+}
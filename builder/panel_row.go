@@ -14,3 +14,14 @@ func NewPanelRow(title string) PanelFactory {
 		}
 	}
 }
+
+// NewPanelCollapsedRow creates a row which is collapsed when the dashboard is loaded. The panels following the row are
+// moved into it by the DashboardBuilder.
+func NewPanelCollapsedRow(title string) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		panel := NewPanelRow(title)(settings)
+		panel.Collapsed = true
+
+		return panel
+	}
+}
@@ -0,0 +1,132 @@
+package builder
+
+func newSnsTopicTarget(topic MetadataCloudAwsSnsTopic, metricName string, statistic string, alias string, refId string) PanelTargetCloudWatch {
+	return PanelTargetCloudWatch{
+		Alias: alias,
+		Dimensions: map[string]string{
+			"TopicName": topic.TopicName,
+		},
+		MatchExact: false,
+		MetricName: metricName,
+		Namespace:  "AWS/SNS",
+		RefId:      refId,
+		Region:     "default",
+		Statistics: []string{
+			statistic,
+		},
+	}
+}
+
+func NewPanelSnsMessagesPublished(topic MetadataCloudAwsSnsTopic) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		return Panel{
+			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Custom: PanelFieldConfigDefaultsCustom{
+						SpanNulls:     true,
+						LineWidth:     2,
+						AxisPlacement: "right",
+					},
+					Min: "0",
+				},
+				Overrides: []PanelFieldConfigOverride{},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				newSnsTopicTarget(topic, "NumberOfMessagesPublished", "Sum", "Published", "A"),
+			},
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "Messages Published",
+			Type:    "timeseries",
+		}
+	}
+}
+
+func NewPanelSnsNotifications(topic MetadataCloudAwsSnsTopic) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		return Panel{
+			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Custom: PanelFieldConfigDefaultsCustom{
+						SpanNulls:     true,
+						LineWidth:     2,
+						AxisPlacement: "right",
+					},
+					Min: "0",
+				},
+				Overrides: []PanelFieldConfigOverride{
+					NewColorPropertyOverride("Delivered", settings.theme.Color(ThemeRoleSuccess), ""),
+					NewColorPropertyOverride("Failed", settings.theme.Color(ThemeRoleError), ""),
+				},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				newSnsTopicTarget(topic, "NumberOfNotificationsDelivered", "Sum", "Delivered", "A"),
+				newSnsTopicTarget(topic, "NumberOfNotificationsFailed", "Sum", "Failed", "B"),
+			},
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "Notifications",
+			Type:    "timeseries",
+		}
+	}
+}
+
+func NewPanelSnsPublishSize(topic MetadataCloudAwsSnsTopic) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		return Panel{
+			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Custom: PanelFieldConfigDefaultsCustom{
+						SpanNulls:     true,
+						LineWidth:     2,
+						AxisPlacement: "right",
+					},
+					Min:  "0",
+					Unit: "bytes",
+				},
+				Overrides: []PanelFieldConfigOverride{},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				newSnsTopicTarget(topic, "PublishSize", "Average", "Average", "A"),
+				newSnsTopicTarget(topic, "PublishSize", "Maximum", "Maximum", "B"),
+			},
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "Publish Size",
+			Type:    "timeseries",
+		}
+	}
+}
+
+func NewPanelSnsFilteredNotifications(topic MetadataCloudAwsSnsTopic) PanelFactory {
+	return func(settings PanelSettings) Panel {
+		return Panel{
+			Datasource: settings.resourceNames.GrafanaCloudWatchDatasourceName,
+			FieldConfig: PanelFieldConfig{
+				Defaults: PanelFieldConfigDefaults{
+					Custom: PanelFieldConfigDefaultsCustom{
+						SpanNulls:     true,
+						LineWidth:     2,
+						AxisPlacement: "right",
+					},
+					Min: "0",
+				},
+				Overrides: []PanelFieldConfigOverride{
+					NewColorPropertyOverride("Failed To Redrive To DLQ", settings.theme.Color(ThemeRoleError), ""),
+				},
+			},
+			GridPos: settings.gridPos,
+			Targets: []any{
+				newSnsTopicTarget(topic, "NumberOfNotificationsFilteredOut", "Sum", "Filtered Out", "A"),
+				newSnsTopicTarget(topic, "NumberOfNotificationsRedrivenToDlq", "Sum", "Redriven To DLQ", "B"),
+				newSnsTopicTarget(topic, "NumberOfNotificationsFailedToRedriveToDlq", "Sum", "Failed To Redrive To DLQ", "C"),
+			},
+			Options: &PanelOptionsCloudWatch{},
+			Title:   "Filtered And Redriven Notifications",
+			Type:    "timeseries",
+		}
+	}
+}
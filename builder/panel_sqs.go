@@ -7,11 +7,13 @@ func NewPanelSqsMessagesVisible(queue MetadataCloudAwsSqsQueue) PanelFactory {
 			FieldConfig: PanelFieldConfig{
 				Defaults: PanelFieldConfigDefaults{
 					Custom: PanelFieldConfigDefaultsCustom{
-						SpanNulls:     true,
-						LineWidth:     2,
-						AxisPlacement: "right",
+						SpanNulls:       true,
+						LineWidth:       2,
+						AxisPlacement:   "right",
+						ThresholdsStyle: newPanelThresholdsStyle(settings, PanelKindSqsMessagesVisible),
 					},
-					Min: "0",
+					Min:        "0",
+					Thresholds: newPanelThresholdLines(settings, PanelKindSqsMessagesVisible),
 				},
 				Overrides: []PanelFieldConfigOverride{},
 			},
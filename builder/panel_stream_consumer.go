@@ -14,8 +14,8 @@ func NewPanelStreamConsumerProcessedCount(consumer MetadataStreamConsumer) Panel
 					Min: "0",
 				},
 				Overrides: []PanelFieldConfigOverride{
-					NewColorPropertyOverride("Processed", "super-light-blue", ""),
-					NewColorPropertyOverride("Error", "dark-red", ""),
+					NewColorPropertyOverride("Processed", settings.theme.Color(ThemeRoleTraffic), ""),
+					NewColorPropertyOverride("Error", settings.theme.Color(ThemeRoleError), ""),
 				},
 			},
 			GridPos: settings.gridPos,
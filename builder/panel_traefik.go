@@ -12,7 +12,7 @@ func NewPanelTraefikRequestCount(settings PanelSettings) Panel {
 				Min: "0",
 			},
 			Overrides: []PanelFieldConfigOverride{
-				NewColorPropertyOverride("Requests", "semi-dark-blue", ""),
+				NewColorPropertyOverride("Requests", settings.theme.Color(ThemeRoleRequests), ""),
 			},
 		},
 		GridPos: settings.gridPos,
@@ -41,18 +41,16 @@ func NewPanelTraefikResponseTime(settings PanelSettings) Panel {
 				Unit: "s",
 			},
 			Overrides: []PanelFieldConfigOverride{
-				NewColorPropertyOverride("Response Time", "semi-dark-blue", ""),
+				NewColorPropertyOverride("Response Time", settings.theme.Color(ThemeRoleLatency), ""),
 			},
 		},
 		GridPos: settings.gridPos,
-		Targets: []any{
-			PanelTargetPrometheus{
-				Exemplar:     true,
-				Expression:   fmt.Sprintf(`sum(irate(traefik_service_request_duration_seconds_sum{%s}[$__rate_interval])) / sum(irate(traefik_service_requests_total{%s}[$__rate_interval]))`, labelFilter, labelFilter),
-				LegendFormat: "Response Time",
-				RefId:        "Requests",
-			},
-		},
+		Targets: newPercentileTargetsPrometheus(settings, PanelTargetPrometheus{
+			Exemplar:     true,
+			Expression:   fmt.Sprintf(`sum(irate(traefik_service_request_duration_seconds_sum{%s}[$__rate_interval])) / sum(irate(traefik_service_requests_total{%s}[$__rate_interval]))`, labelFilter, labelFilter),
+			LegendFormat: "Response Time",
+			RefId:        "Requests",
+		}, "traefik_service_request_duration_seconds_bucket", labelFilter),
 		Options: &PanelOptionsCloudWatch{},
 		Title:   "Response Time",
 		Type:    "timeseries",
@@ -69,10 +67,10 @@ func NewPanelTraefikHttpStatus(settings PanelSettings) Panel {
 				Min: "0",
 			},
 			Overrides: []PanelFieldConfigOverride{
-				NewColorPropertyOverride("HTTP 2XX", "semi-dark-green", ""),
-				NewColorPropertyOverride("HTTP 3XX", "semi-dark-yellow", ""),
-				NewColorPropertyOverride("HTTP 4XX", "semi-dark-orange", ""),
-				NewColorPropertyOverride("HTTP 5XX", "dark-red", ""),
+				NewColorPropertyOverride("HTTP 2XX", settings.theme.Color(ThemeRoleSuccess), ""),
+				NewColorPropertyOverride("HTTP 3XX", settings.theme.Color(ThemeRoleRedirect), ""),
+				NewColorPropertyOverride("HTTP 4XX", settings.theme.Color(ThemeRoleClientError), ""),
+				NewColorPropertyOverride("HTTP 5XX", settings.theme.Color(ThemeRoleError), ""),
 			},
 		},
 		GridPos: settings.gridPos,
@@ -109,7 +107,8 @@ func NewPanelTraefikHttpStatus(settings PanelSettings) Panel {
 }
 
 func NewPanelKubernetesHealthyPods(settings PanelSettings) Panel {
-	labelFilter := getKubernetesPodLabelFilter(settings.resourceNames.KubernetesNamespace, settings.resourceNames.KubernetesPod)
+	labelFilter := getKubernetesPodLabelFilter(settings.resourceNames)
+	selectorJoin := getKubernetesPodSelectorJoin(settings.resourceNames)
 
 	return Panel{
 		Datasource: datasourcePrometheus,
@@ -123,7 +122,7 @@ func NewPanelKubernetesHealthyPods(settings PanelSettings) Panel {
 		Targets: []any{
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   fmt.Sprintf(`count(kube_pod_status_ready{condition="true",%s})`, labelFilter),
+				Expression:   fmt.Sprintf(`count(kube_pod_status_ready{condition="true",%s}%s)`, labelFilter, selectorJoin),
 				LegendFormat: "Healthy Endpoints",
 				RefId:        "A",
 			},
@@ -136,7 +135,8 @@ func NewPanelKubernetesHealthyPods(settings PanelSettings) Panel {
 
 func NewPanelTraefikRequestCountPerTarget(settings PanelSettings) Panel {
 	labelFilterTraefik := getTraefikServiceLabelFilter(settings.resourceNames.TraefikServiceName)
-	labelFilterPod := getKubernetesPodLabelFilter(settings.resourceNames.KubernetesNamespace, settings.resourceNames.KubernetesPod)
+	labelFilterPod := getKubernetesPodLabelFilter(settings.resourceNames)
+	selectorJoin := getKubernetesPodSelectorJoin(settings.resourceNames)
 
 	return Panel{
 		Datasource: datasourcePrometheus,
@@ -150,7 +150,7 @@ func NewPanelTraefikRequestCountPerTarget(settings PanelSettings) Panel {
 		Targets: []any{
 			PanelTargetPrometheus{
 				Exemplar:     true,
-				Expression:   fmt.Sprintf(`sum(irate(traefik_service_requests_total{%s}[1m])) by () * 60/count(kube_pod_status_ready{condition="true",%s})`, labelFilterTraefik, labelFilterPod),
+				Expression:   fmt.Sprintf(`sum(irate(traefik_service_requests_total{%s}[1m])) by () * 60/count(kube_pod_status_ready{condition="true",%s}%s)`, labelFilterTraefik, labelFilterPod, selectorJoin),
 				LegendFormat: "Requests",
 				RefId:        "A",
 			},
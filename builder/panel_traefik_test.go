@@ -22,7 +22,7 @@ func TestTraefikResponseTimePanelIntegration(t *testing.T) {
 		Containers:                         []string{"app"},
 	}
 
-	db := builder.NewDashboardBuilder(resourceNames, "kubernetes")
+	db := newDashboardBuilder(t, resourceNames, "kubernetes")
 	db.AddTraefikService()
 
 	dashboard := db.Build("test dashboard")
@@ -0,0 +1,79 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Percentile of a latency distribution, e.g. 99 or 99.9
+type Percentile float64
+
+// NewPercentiles validates the percentiles and returns them sorted and without duplicates
+func NewPercentiles(values ...float64) ([]Percentile, error) {
+	percentiles := make([]Percentile, 0, len(values))
+	seen := make(map[float64]bool)
+
+	for _, value := range values {
+		if value <= 0 || value >= 100 {
+			return nil, fmt.Errorf("the percentile %s has to be between 0 and 100", strconv.FormatFloat(value, 'f', -1, 64))
+		}
+
+		if seen[value] {
+			continue
+		}
+
+		seen[value] = true
+		percentiles = append(percentiles, Percentile(value))
+	}
+
+	sort.Slice(percentiles, func(i, j int) bool {
+		return percentiles[i] < percentiles[j]
+	})
+
+	return percentiles, nil
+}
+
+// Name is the name of the percentile, which is also its cloudwatch extended statistic, e.g. p99.9
+func (p Percentile) Name() string {
+	return "p" + strconv.FormatFloat(float64(p), 'f', -1, 64)
+}
+
+// Quantile is the percentile as a quantile for histogram_quantile, e.g. 0.999
+func (p Percentile) Quantile() string {
+	// the division isn't exact for percentiles like 99.9, the precision drops the rounding error
+	return strconv.FormatFloat(float64(p)/100, 'g', 10, 64)
+}
+
+// newPercentileTargetsCloudWatch adds a target per percentile, which are copies of the average target with an
+// extended statistic
+func newPercentileTargetsCloudWatch(settings PanelSettings, average PanelTargetCloudWatch) []any {
+	targets := []any{average}
+
+	for _, percentile := range settings.percentiles {
+		target := average
+		target.Alias = percentile.Name()
+		target.RefId = percentile.Name()
+		target.Statistics = []string{percentile.Name()}
+
+		targets = append(targets, target)
+	}
+
+	return targets
+}
+
+// newPercentileTargetsPrometheus adds a target per percentile, computed from the buckets of the histogram
+func newPercentileTargetsPrometheus(settings PanelSettings, average PanelTargetPrometheus, bucket string, labelFilter string) []any {
+	targets := []any{average}
+
+	for _, percentile := range settings.percentiles {
+		targets = append(targets, PanelTargetPrometheus{
+			Exemplar:     true,
+			Expression:   fmt.Sprintf(`histogram_quantile(%s, sum by (le) (rate(%s{%s}[$__rate_interval])))`, percentile.Quantile(), bucket, labelFilter),
+			LegendFormat: percentile.Name(),
+			RefId:        percentile.Name(),
+		})
+	}
+
+	return targets
+}
@@ -0,0 +1,57 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPercentiles(t *testing.T) {
+	percentiles, err := builder.NewPercentiles(99.9, 50, 99, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, []builder.Percentile{50, 99, 99.9}, percentiles)
+
+	assert.Equal(t, "p99.9", percentiles[2].Name())
+	assert.Equal(t, "0.999", percentiles[2].Quantile())
+	assert.Equal(t, "0.5", percentiles[0].Quantile())
+
+	_, err = builder.NewPercentiles(100)
+	assert.EqualError(t, err, "the percentile 100 has to be between 0 and 100")
+}
+
+func TestPercentilePanels(t *testing.T) {
+	percentiles, err := builder.NewPercentiles(50, 99)
+	assert.NoError(t, err)
+
+	resourceNames := provideIngressResourceNames()
+	resourceNames.TargetGroups = []builder.ElbTargetGroup{{LoadBalancer: "lb", TargetGroup: "tg"}}
+
+	db := newDashboardBuilder(t, resourceNames, "ecs", builder.WithPercentiles(percentiles))
+	db.AddElbTargetGroup(0)
+	db.AddHttpServerHandler("default", builder.MetadataHttpServerHandler{Method: "GET", Path: "/v1/orders"})
+	dashboard := db.Build("")
+
+	for _, panel := range []builder.Panel{dashboard.Panels[2], dashboard.Panels[8]} {
+		assert.Equal(t, "Response Time", panel.Title)
+		assert.Len(t, panel.Targets, 3)
+
+		average := panel.Targets[0].(builder.PanelTargetCloudWatch)
+		p99 := panel.Targets[2].(builder.PanelTargetCloudWatch)
+
+		assert.Equal(t, []string{"Average"}, average.Statistics)
+		assert.Equal(t, []string{"p99"}, p99.Statistics)
+		assert.Equal(t, "p99", p99.Alias)
+		assert.Equal(t, average.MetricName, p99.MetricName)
+		assert.Equal(t, average.Dimensions, p99.Dimensions)
+	}
+
+	db = newDashboardBuilder(t, provideIngressResourceNames(), "kubernetes", builder.WithPercentiles(percentiles))
+	db.AddIngress()
+	dashboard = db.Build("")
+
+	responseTime := dashboard.Panels[2]
+	assert.Len(t, responseTime.Targets, 3)
+	assert.Equal(t, `histogram_quantile(0.99, sum by (le) (rate(traefik_service_request_duration_seconds_bucket{service="prj-grp-app-8080@kubernetes"}[$__rate_interval])))`, responseTime.Targets[2].(builder.PanelTargetPrometheus).Expression)
+	assert.Equal(t, "p50", responseTime.Targets[1].(builder.PanelTargetPrometheus).LegendFormat)
+}
@@ -44,7 +44,7 @@ func TestKubernetesPodLabelFilterRegex(t *testing.T) {
 			}
 
 			// Create a panel and check that the generated expression uses the new regex pattern
-			db := builder.NewDashboardBuilder(resourceNames, "kubernetes")
+			db := newDashboardBuilder(t, resourceNames, "kubernetes")
 			db.AddPanel(builder.NewPanelKubernetesHealthyPods)
 			dashboard := db.Build("test")
 
@@ -0,0 +1,225 @@
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PrometheusRulesSettings configure the alerting rules of a kubernetes application. A rule fires if its value is above
+// the threshold for the For duration, a threshold of 0 disables the rule.
+type PrometheusRulesSettings struct {
+	For string
+	// PodRestarts is the number of container restarts within 15 minutes
+	PodRestarts int
+	// CpuSaturation and MemorySaturation are the usage in percent of the requests
+	CpuSaturation    float64
+	MemorySaturation float64
+	// MissingReplicas is the share of the desired replicas in percent which aren't ready
+	MissingReplicas float64
+	// Http5xxRatio is the share of the traefik requests in percent which failed with a 5xx status
+	Http5xxRatio float64
+	// LatencyP99 is the p99 latency of the traefik requests in seconds
+	LatencyP99 float64
+	// IncludeTraefik adds the rules of the traefik service, which are skipped for the other ingresses
+	IncludeTraefik bool
+	// Labels are added to every rule, e.g. to route the alerts
+	Labels map[string]string
+}
+
+type PrometheusRuleGroup struct {
+	Name  string           `yaml:"name"`
+	Rules []PrometheusRule `yaml:"rules"`
+}
+
+type PrometheusRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type prometheusRuleFile struct {
+	Groups []PrometheusRuleGroup `yaml:"groups"`
+}
+
+type prometheusRuleManifest struct {
+	ApiVersion string                     `yaml:"apiVersion"`
+	Kind       string                     `yaml:"kind"`
+	Metadata   prometheusRuleMetadata     `yaml:"metadata"`
+	Spec       prometheusRuleManifestSpec `yaml:"spec"`
+}
+
+type prometheusRuleMetadata struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+type prometheusRuleManifestSpec struct {
+	Groups []PrometheusRuleGroup `yaml:"groups"`
+}
+
+func DefaultPrometheusRulesSettings() PrometheusRulesSettings {
+	return PrometheusRulesSettings{
+		For:              "5m",
+		PodRestarts:      2,
+		CpuSaturation:    90,
+		MemorySaturation: 90,
+		MissingReplicas:  1,
+		Http5xxRatio:     5,
+		LatencyP99:       1,
+		IncludeTraefik:   true,
+	}
+}
+
+// NewPrometheusRuleGroup creates the rules for the pod restarts, the cpu and memory saturation, the ready replicas and,
+// if enabled, the traefik service. They use the same label filters as the panels of the dashboard.
+func NewPrometheusRuleGroup(resourceNames *ResourceNames, settings PrometheusRulesSettings) (PrometheusRuleGroup, error) {
+	for name, value := range map[string]float64{
+		"pod restarts":      float64(settings.PodRestarts),
+		"cpu saturation":    settings.CpuSaturation,
+		"memory saturation": settings.MemorySaturation,
+		"missing replicas":  settings.MissingReplicas,
+		"5xx ratio":         settings.Http5xxRatio,
+		"p99 latency":       settings.LatencyP99,
+	} {
+		if value < 0 {
+			return PrometheusRuleGroup{}, fmt.Errorf("the threshold of the %s can not be negative, got %v", name, value)
+		}
+	}
+
+	workload := fmt.Sprintf("%s/%s", resourceNames.KubernetesNamespace, resourceNames.KubernetesDeployment)
+	podLabelFilter := getKubernetesPodLabelFilter(resourceNames)
+	cpuQuery, memoryQuery := kubernetesOrchestrator{}.ServiceUtilizationQueries(resourceNames)
+	queries := getKubernetesWorkloadQueries(resourceNames)
+
+	rules := make([]PrometheusRule, 0)
+	addRule := func(alert string, expr string, threshold float64, summary string) {
+		if threshold <= 0 {
+			return
+		}
+
+		rules = append(rules, newPrometheusRule(settings, alert, fmt.Sprintf("%s > %v", expr, threshold), summary))
+	}
+
+	addRule(
+		"PodRestarts",
+		fmt.Sprintf(`sum(increase(kube_pod_container_status_restarts_total{%s}[15m])%s)`, podLabelFilter, getKubernetesPodSelectorJoin(resourceNames)),
+		float64(settings.PodRestarts),
+		fmt.Sprintf("The containers of %s restarted more than %d times within 15 minutes", workload, settings.PodRestarts),
+	)
+	addRule(
+		"CpuSaturation",
+		normalizePromQL(cpuQuery),
+		settings.CpuSaturation,
+		fmt.Sprintf("The pods of %s use more than %v%% of their cpu requests", workload, settings.CpuSaturation),
+	)
+	addRule(
+		"MemorySaturation",
+		normalizePromQL(memoryQuery),
+		settings.MemorySaturation,
+		fmt.Sprintf("The pods of %s use more than %v%% of their memory requests", workload, settings.MemorySaturation),
+	)
+
+	desiredReplicaQuery := fmt.Sprintf(queries.desiredReplicaQuery, resourceNames.KubernetesNamespace, resourceNames.KubernetesDeployment)
+	replicaQuery := fmt.Sprintf(queries.replicaQuery, resourceNames.KubernetesNamespace, resourceNames.KubernetesDeployment)
+
+	addRule(
+		"ReplicasBelowDesired",
+		fmt.Sprintf("(%s - %s) / %s * 100", desiredReplicaQuery, replicaQuery, desiredReplicaQuery),
+		settings.MissingReplicas,
+		fmt.Sprintf("More than %v%% of the desired replicas of %s aren't ready", settings.MissingReplicas, workload),
+	)
+
+	if settings.IncludeTraefik {
+		labelFilter := getTraefikServiceLabelFilter(resourceNames.TraefikServiceName)
+
+		addRule(
+			"Http5xxRatio",
+			fmt.Sprintf(
+				`sum(rate(traefik_service_requests_total{code=~"5.*",%s}[5m])) / sum(rate(traefik_service_requests_total{%s}[5m])) * 100`,
+				labelFilter,
+				labelFilter,
+			),
+			settings.Http5xxRatio,
+			fmt.Sprintf("More than %v%% of the requests to %s failed with a 5xx status", settings.Http5xxRatio, workload),
+		)
+		addRule(
+			"HttpLatencyP99",
+			fmt.Sprintf(
+				`histogram_quantile(%s, sum by (le) (rate(traefik_service_request_duration_seconds_bucket{%s}[5m])))`,
+				Percentile(99).Quantile(),
+				labelFilter,
+			),
+			settings.LatencyP99,
+			fmt.Sprintf("The p99 latency of the requests to %s is above %vs", workload, settings.LatencyP99),
+		)
+	}
+
+	return PrometheusRuleGroup{
+		Name:  fmt.Sprintf("%s-%s", resourceNames.KubernetesNamespace, resourceNames.KubernetesDeployment),
+		Rules: rules,
+	}, nil
+}
+
+func newPrometheusRule(settings PrometheusRulesSettings, alert string, expr string, summary string) PrometheusRule {
+	return PrometheusRule{
+		Alert:  alert,
+		Expr:   expr,
+		For:    settings.For,
+		Labels: settings.Labels,
+		Annotations: map[string]string{
+			"summary": summary,
+		},
+	}
+}
+
+// normalizePromQL puts a query on a single line, the queries shared with the panels are indented for readability
+func normalizePromQL(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// RuleFile renders the group as a prometheus rule file
+func (g PrometheusRuleGroup) RuleFile() ([]byte, error) {
+	return marshalYaml(prometheusRuleFile{
+		Groups: []PrometheusRuleGroup{g},
+	})
+}
+
+// Manifest renders the group as a PrometheusRule of the prometheus operator
+func (g PrometheusRuleGroup) Manifest(namespace string, labels map[string]string) ([]byte, error) {
+	return marshalYaml(prometheusRuleManifest{
+		ApiVersion: "monitoring.coreos.com/v1",
+		Kind:       "PrometheusRule",
+		Metadata: prometheusRuleMetadata{
+			Name:      g.Name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: prometheusRuleManifestSpec{
+			Groups: []PrometheusRuleGroup{g},
+		},
+	})
+}
+
+// marshalYaml indents by two spaces, like kubernetes manifests usually are
+func marshalYaml(value any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(value); err != nil {
+		return nil, fmt.Errorf("can not encode yaml: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("can not encode yaml: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
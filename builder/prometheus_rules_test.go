@@ -0,0 +1,86 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewPrometheusRuleGroup(t *testing.T) {
+	settings := builder.DefaultPrometheusRulesSettings()
+	settings.MemorySaturation = 0
+	settings.Labels = map[string]string{"team": "platform"}
+
+	group, err := builder.NewPrometheusRuleGroup(provideIngressResourceNames(), settings)
+	assert.NoError(t, err)
+	assert.Equal(t, "prj-grp-app", group.Name)
+
+	alerts := make([]string, len(group.Rules))
+	for i, rule := range group.Rules {
+		alerts[i] = rule.Alert
+
+		assert.Equal(t, "5m", rule.For)
+		assert.Equal(t, map[string]string{"team": "platform"}, rule.Labels)
+	}
+
+	assert.Equal(t, []string{"PodRestarts", "CpuSaturation", "ReplicasBelowDesired", "Http5xxRatio", "HttpLatencyP99"}, alerts)
+	assert.Equal(t, `sum(increase(kube_pod_container_status_restarts_total{namespace="prj", pod=~"^grp-app-[0-9a-f]+-[0-9a-z]+$"}[15m])) > 2`, group.Rules[0].Expr)
+	assert.Equal(t, `(sum(kube_deployment_spec_replicas{namespace="prj", deployment="grp-app"}) - sum(kube_deployment_status_replicas_ready{namespace="prj", deployment="grp-app"})) / sum(kube_deployment_spec_replicas{namespace="prj", deployment="grp-app"}) * 100 > 1`, group.Rules[2].Expr)
+	assert.Equal(t, `histogram_quantile(0.99, sum by (le) (rate(traefik_service_request_duration_seconds_bucket{service="prj-grp-app-8080@kubernetes"}[5m]))) > 1`, group.Rules[4].Expr)
+	assert.NotContains(t, group.Rules[1].Expr, "\n")
+
+	settings.IncludeTraefik = false
+	group, err = builder.NewPrometheusRuleGroup(provideIngressResourceNames(), settings)
+	assert.NoError(t, err)
+	assert.Len(t, group.Rules, 3)
+
+	settings.MissingReplicas = 0
+	group, err = builder.NewPrometheusRuleGroup(provideIngressResourceNames(), settings)
+	assert.NoError(t, err)
+	assert.Len(t, group.Rules, 2)
+	assert.NotContains(t, []string{group.Rules[0].Alert, group.Rules[1].Alert}, "ReplicasBelowDesired")
+
+	settings.LatencyP99 = -1
+	_, err = builder.NewPrometheusRuleGroup(provideIngressResourceNames(), settings)
+	assert.EqualError(t, err, "the threshold of the p99 latency can not be negative, got -1")
+}
+
+func TestPrometheusRuleGroupManifest(t *testing.T) {
+	group, err := builder.NewPrometheusRuleGroup(provideIngressResourceNames(), builder.DefaultPrometheusRulesSettings())
+	assert.NoError(t, err)
+
+	body, err := group.Manifest("monitoring", map[string]string{"release": "prometheus"})
+	assert.NoError(t, err)
+
+	manifest := struct {
+		ApiVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name      string            `yaml:"name"`
+			Namespace string            `yaml:"namespace"`
+			Labels    map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Groups []builder.PrometheusRuleGroup `yaml:"groups"`
+		} `yaml:"spec"`
+	}{}
+	assert.NoError(t, yaml.Unmarshal(body, &manifest))
+
+	assert.Equal(t, "monitoring.coreos.com/v1", manifest.ApiVersion)
+	assert.Equal(t, "PrometheusRule", manifest.Kind)
+	assert.Equal(t, "prj-grp-app", manifest.Metadata.Name)
+	assert.Equal(t, "monitoring", manifest.Metadata.Namespace)
+	assert.Equal(t, map[string]string{"release": "prometheus"}, manifest.Metadata.Labels)
+	assert.Equal(t, []builder.PrometheusRuleGroup{group}, manifest.Spec.Groups)
+
+	body, err = group.RuleFile()
+	assert.NoError(t, err)
+
+	ruleFile := struct {
+		Groups []builder.PrometheusRuleGroup `yaml:"groups"`
+	}{}
+	assert.NoError(t, yaml.Unmarshal(body, &ruleFile))
+	assert.Equal(t, []builder.PrometheusRuleGroup{group}, ruleFile.Groups)
+}
@@ -9,9 +9,13 @@ type ResourceNames struct {
 	Environment                        string
 	GrafanaCloudWatchDatasourceName    string
 	GrafanaElasticsearchDatasourceName string
+	IstioServiceName                   string
 	KubernetesDeployment               string
 	KubernetesNamespace                string
 	KubernetesPod                      string
+	KubernetesPodSelector              []string
+	KubernetesWorkloadKind             string
+	NginxIngressName                   string
 	TargetGroups                       []ElbTargetGroup
 	TraefikServiceName                 string
 }
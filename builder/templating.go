@@ -0,0 +1,190 @@
+package builder
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/thoas/go-funk"
+)
+
+const (
+	TemplateVariableDatasourceCloudWatch = "datasource_cloudwatch"
+	TemplateVariableDatasourcePrometheus = "datasource_prometheus"
+	TemplateVariableContainer            = "container"
+	TemplateVariableRoute                = "route"
+	TemplateVariableInterval             = "interval"
+
+	templateVariableAll       = "$__all"
+	templateVariableIntervals = "1m,5m,10m,30m,1h"
+)
+
+// prometheusRangeWindow matches the range windows of the prometheus queries of the panels which follow the interval
+// variable, longer windows like the ones detecting changes are kept
+var prometheusRangeWindow = regexp.MustCompile(`\[(\$__rate_interval|1m|5m)\]`)
+
+type DashboardTemplating struct {
+	List []TemplateVariable `json:"list"`
+}
+
+type TemplateVariable struct {
+	Current    TemplateVariableOption   `json:"current"`
+	Hide       int                      `json:"hide"`
+	IncludeAll bool                     `json:"includeAll"`
+	Label      string                   `json:"label"`
+	Multi      bool                     `json:"multi"`
+	Name       string                   `json:"name"`
+	Options    []TemplateVariableOption `json:"options"`
+	Query      string                   `json:"query"`
+	Type       string                   `json:"type"`
+}
+
+type TemplateVariableOption struct {
+	Selected bool   `json:"selected"`
+	Text     string `json:"text"`
+	Value    string `json:"value"`
+}
+
+// TemplateVariableRef returns the reference to a template variable which can be used in panel targets and titles
+func TemplateVariableRef(name string) string {
+	return fmt.Sprintf("$%s", name)
+}
+
+// TemplateVariableDatasourceRef returns the reference to a datasource variable which can be used as panel datasource
+func TemplateVariableDatasourceRef(name string) string {
+	return fmt.Sprintf("${%s}", name)
+}
+
+func NewTemplateVariableDatasource(name string, label string, pluginId string, datasourceName string) TemplateVariable {
+	return TemplateVariable{
+		Current: TemplateVariableOption{
+			Selected: true,
+			Text:     datasourceName,
+			Value:    datasourceName,
+		},
+		Label:   label,
+		Name:    name,
+		Options: []TemplateVariableOption{},
+		Query:   pluginId,
+		Type:    "datasource",
+	}
+}
+
+// NewTemplateVariableCustom creates a variable with a fixed set of values of which all are selected by default
+func NewTemplateVariableCustom(name string, label string, values []string) TemplateVariable {
+	options := make([]TemplateVariableOption, 0, len(values)+1)
+	options = append(options, TemplateVariableOption{
+		Selected: true,
+		Text:     "All",
+		Value:    templateVariableAll,
+	})
+
+	for _, value := range values {
+		options = append(options, TemplateVariableOption{
+			Text:  value,
+			Value: value,
+		})
+	}
+
+	return TemplateVariable{
+		Current:    options[0],
+		IncludeAll: true,
+		Label:      label,
+		Multi:      true,
+		Name:       name,
+		Options:    options,
+		Query:      strings.Join(values, ","),
+		Type:       "custom",
+	}
+}
+
+func NewTemplateVariableInterval(name string, label string, intervals string) TemplateVariable {
+	values := strings.Split(intervals, ",")
+	options := make([]TemplateVariableOption, len(values))
+
+	for i, value := range values {
+		options[i] = TemplateVariableOption{
+			Selected: i == 0,
+			Text:     value,
+			Value:    value,
+		}
+	}
+
+	return TemplateVariable{
+		Current: options[0],
+		Label:   label,
+		Name:    name,
+		Options: options,
+		Query:   intervals,
+		Type:    "interval",
+	}
+}
+
+// dashboardTemplating collects the values of the template variables while the sections are added to the dashboard
+type dashboardTemplating struct {
+	routes []string
+}
+
+func (t *dashboardTemplating) addRoute(handler MetadataHttpServerHandler) {
+	if funk.ContainsString(t.routes, handler.Path) {
+		return
+	}
+
+	t.routes = append(t.routes, handler.Path)
+}
+
+func (t *dashboardTemplating) build(resourceNames *ResourceNames) *DashboardTemplating {
+	routes := append([]string{}, t.routes...)
+	sort.Strings(routes)
+
+	variables := []TemplateVariable{
+		NewTemplateVariableDatasource(TemplateVariableDatasourceCloudWatch, "CloudWatch", "cloudwatch", resourceNames.GrafanaCloudWatchDatasourceName),
+		NewTemplateVariableDatasource(TemplateVariableDatasourcePrometheus, "Prometheus", "prometheus", datasourcePrometheus),
+	}
+
+	if len(resourceNames.Containers) > 0 {
+		variables = append(variables, NewTemplateVariableCustom(TemplateVariableContainer, "Container", resourceNames.Containers))
+	}
+
+	if len(routes) > 0 {
+		variables = append(variables, NewTemplateVariableCustom(TemplateVariableRoute, "Route", routes))
+	}
+
+	variables = append(variables, NewTemplateVariableInterval(TemplateVariableInterval, "Interval", templateVariableIntervals))
+
+	return &DashboardTemplating{
+		List: variables,
+	}
+}
+
+// applyTemplateVariableInterval lets the prometheus range windows and the cloudwatch periods of the panel follow the
+// interval variable. Panels supplied as raw json are kept as they are.
+func applyTemplateVariableInterval(panel Panel) Panel {
+	if panel.Raw != nil || len(panel.Targets) == 0 {
+		return panel
+	}
+
+	interval := TemplateVariableRef(TemplateVariableInterval)
+	targets := make([]any, len(panel.Targets))
+
+	for i, target := range panel.Targets {
+		switch t := target.(type) {
+		case PanelTargetPrometheus:
+			t.Expression = prometheusRangeWindow.ReplaceAllLiteralString(t.Expression, fmt.Sprintf("[%s]", interval))
+			targets[i] = t
+		case PanelTargetCloudWatch:
+			if t.Period == "" {
+				t.Period = interval
+			}
+
+			targets[i] = t
+		default:
+			targets[i] = target
+		}
+	}
+
+	panel.Targets = targets
+
+	return panel
+}
@@ -0,0 +1,118 @@
+package builder_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardTemplating(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		EcsCluster:                      "cluster",
+		EcsTaskDefinition:               "task-def",
+		Environment:                     "test",
+		GrafanaCloudWatchDatasourceName: "cw",
+		Containers:                      []string{"app", "log_router"},
+	}
+
+	db := newDashboardBuilder(t, resourceNames, "ecs", builder.WithTemplating())
+	db.AddServiceAndTask()
+	db.AddHttpServerHandler("default", builder.MetadataHttpServerHandler{Method: "POST", Path: "/b"})
+	db.AddHttpServerHandler("default", builder.MetadataHttpServerHandler{Method: "GET", Path: "/a"})
+	db.AddHttpServerHandler("default", builder.MetadataHttpServerHandler{Method: "PUT", Path: "/a"})
+	dashboard := db.Build("templating")
+
+	variables := make(map[string]builder.TemplateVariable)
+	names := make([]string, 0)
+
+	for _, variable := range dashboard.Templating.List {
+		variables[variable.Name] = variable
+		names = append(names, variable.Name)
+	}
+
+	assert.Equal(t, []string{"datasource_cloudwatch", "datasource_prometheus", "container", "route", "interval"}, names)
+	assert.Equal(t, "cw", variables["datasource_cloudwatch"].Current.Value)
+	assert.Equal(t, "app,log_router", variables["container"].Query)
+	assert.Equal(t, "/a,/b", variables["route"].Query)
+	assert.Equal(t, "interval", variables["interval"].Type)
+
+	// the container panels are rendered once and repeated for every selected container
+	assert.Len(t, dashboard.Panels, 9)
+	cpu := dashboard.Panels[3]
+	assert.Equal(t, "CPU Utilization ($container)", cpu.Title)
+	assert.Equal(t, "container", cpu.Repeat)
+	assert.Equal(t, "${datasource_prometheus}", cpu.Datasource)
+	assert.Contains(t, cpu.Targets[0].(builder.PanelTargetPrometheus).Expression, `container_label_com_amazonaws_ecs_container_name="$container"`)
+	assert.Equal(t, "Memory Utilization ($container)", dashboard.Panels[4].Title)
+
+	// the range windows and periods follow the interval variable
+	utilization := findPanel(t, dashboard, "Service Utilization")
+	assert.Contains(t, utilization.Targets[0].(builder.PanelTargetPrometheus).Expression, `container_cpu_usage_seconds_total{container_label_com_amazonaws_ecs_cluster="cluster", container_label_com_amazonaws_ecs_task_definition_family="task-def"}[$interval]`)
+	assert.NotContains(t, utilization.Targets[0].(builder.PanelTargetPrometheus).Expression, "$__rate_interval")
+
+	// the running tasks aren't repeated, so they must not depend on the selected containers
+	replicas := findPanel(t, dashboard, "Running Task Count")
+	assert.Empty(t, replicas.Repeat)
+	assert.Equal(t, `count(count by (container_label_com_amazonaws_ecs_task_arn) (container_cpu_load_average_10s{container_label_com_amazonaws_ecs_cluster="cluster", container_label_com_amazonaws_ecs_task_definition_family="task-def"}))`, replicas.Targets[0].(builder.PanelTargetPrometheus).Expression)
+
+	// the route section is rendered once and repeated for every selected route
+	row := dashboard.Panels[5]
+	assert.Equal(t, "HttpServer: $route", row.Title)
+	assert.Equal(t, "route", row.Repeat)
+
+	requestCount := dashboard.Panels[6]
+	assert.Equal(t, "${datasource_cloudwatch}", requestCount.Datasource)
+	assert.Equal(t, map[string]string{
+		"Method":     "*",
+		"Path":       "$route",
+		"ServerName": "*",
+	}, requestCount.Targets[0].(builder.PanelTargetCloudWatch).Dimensions)
+	assert.Equal(t, "$interval", requestCount.Targets[0].(builder.PanelTargetCloudWatch).Period)
+
+	_, err := json.Marshal(dashboard)
+	assert.NoError(t, err)
+}
+
+func TestDashboardTemplatingKubernetes(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		KubernetesNamespace:  "prj",
+		KubernetesDeployment: "grp-app",
+		KubernetesPod:        "grp-app",
+		Containers:           []string{"app", "log_router"},
+	}
+
+	db := newDashboardBuilder(t, resourceNames, "kubernetes", builder.WithTemplating())
+	db.AddServiceAndTask()
+	dashboard := db.Build("templating")
+
+	// every repeated container panel shows the series of its own container
+	for _, title := range []string{"CPU Utilization ($container)", "Memory Utilization ($container)"} {
+		panel := findPanel(t, dashboard, title)
+		assert.Equal(t, "container", panel.Repeat)
+
+		for _, target := range panel.Targets {
+			assert.Contains(t, target.(builder.PanelTargetPrometheus).Expression, `container=~"$container"`)
+		}
+	}
+}
+
+func TestDashboardWithoutTemplating(t *testing.T) {
+	resourceNames := &builder.ResourceNames{
+		GrafanaCloudWatchDatasourceName: "cw",
+		Containers:                      []string{"app"},
+	}
+
+	db := newDashboardBuilder(t, resourceNames, "ecs")
+	db.AddServiceAndTask()
+	dashboard := db.Build("")
+
+	assert.Nil(t, dashboard.Templating)
+
+	body, err := json.Marshal(dashboard)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(body), "templating")
+	assert.NotContains(t, string(body), "repeat")
+	assert.NotContains(t, string(body), "$interval")
+}
@@ -0,0 +1,214 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	ThemeRoleAverage             = "average"
+	ThemeRoleClientError         = "client_error"
+	ThemeRoleCpu                 = "cpu"
+	ThemeRoleCpuRequests         = "cpu_requests"
+	ThemeRoleDesired             = "desired"
+	ThemeRoleError               = "error"
+	ThemeRoleLatency             = "latency"
+	ThemeRoleLimits              = "limits"
+	ThemeRoleMaximum             = "maximum"
+	ThemeRoleMemory              = "memory"
+	ThemeRoleMemoryRequests      = "memory_requests"
+	ThemeRoleMinimum             = "minimum"
+	ThemeRoleRedirect            = "redirect"
+	ThemeRoleRequests            = "requests"
+	ThemeRoleReservation         = "reservation"
+	ThemeRoleResourceLimits      = "resource_limits"
+	ThemeRoleSuccess             = "success"
+	ThemeRoleThresholdCritical   = "threshold_critical"
+	ThemeRoleThresholdOk         = "threshold_ok"
+	ThemeRoleThresholdWarning    = "threshold_warning"
+	ThemeRoleTraffic             = "traffic"
+	ThemeRoleUtilizationCritical = "utilization_critical"
+	ThemeRoleUtilizationOk       = "utilization_ok"
+	ThemeRoleWarning             = "warning"
+)
+
+const (
+	PanelKindDdbThrottles       = "ddb_throttles"
+	PanelKindErrors             = "errors"
+	PanelKindHttp5xxRatio       = "http_5xx_ratio"
+	PanelKindHttpResponseTime   = "http_response_time"
+	PanelKindKinsumerLag        = "kinsumer_lag"
+	PanelKindServiceUtilization = "service_utilization"
+	PanelKindSqsMessagesVisible = "sqs_messages_visible"
+)
+
+var defaultThemeColors = map[string]string{
+	ThemeRoleAverage:             "light-orange",
+	ThemeRoleClientError:         "semi-dark-orange",
+	ThemeRoleCpu:                 "light-green",
+	ThemeRoleCpuRequests:         "red",
+	ThemeRoleDesired:             "orange",
+	ThemeRoleError:               "dark-red",
+	ThemeRoleLatency:             "semi-dark-blue",
+	ThemeRoleLimits:              "dark-red",
+	ThemeRoleMaximum:             "light-red",
+	ThemeRoleMemory:              "light-orange",
+	ThemeRoleMemoryRequests:      "semi-dark-red",
+	ThemeRoleMinimum:             "light-green",
+	ThemeRoleRedirect:            "semi-dark-yellow",
+	ThemeRoleRequests:            "semi-dark-blue",
+	ThemeRoleReservation:         "red",
+	ThemeRoleResourceLimits:      "orange",
+	ThemeRoleSuccess:             "semi-dark-green",
+	ThemeRoleThresholdCritical:   "red",
+	ThemeRoleThresholdOk:         "green",
+	ThemeRoleThresholdWarning:    "orange",
+	ThemeRoleTraffic:             "super-light-blue",
+	ThemeRoleUtilizationCritical: "semi-dark-red",
+	ThemeRoleUtilizationOk:       "super-light-green",
+	ThemeRoleWarning:             "dark-yellow",
+}
+
+var defaultThresholds = map[string]PanelThreshold{
+	PanelKindDdbThrottles:       {},
+	PanelKindErrors:             {Critical: 1},
+	PanelKindHttp5xxRatio:       {Warning: 1, Critical: 5},
+	PanelKindHttpResponseTime:   {},
+	PanelKindKinsumerLag:        {Warning: 60000, Critical: 300000},
+	PanelKindServiceUtilization: {Critical: 100},
+	PanelKindSqsMessagesVisible: {},
+}
+
+// Theme maps the semantic roles of the series, like errors or limits, to grafana colors. The zero value is the default theme.
+type Theme struct {
+	colors map[string]string
+}
+
+// PanelThreshold turns a panel into the warning or error color from the given values on, 0 disables a threshold
+type PanelThreshold struct {
+	Warning  int
+	Critical int
+}
+
+// Thresholds of the panel kinds, kinds which aren't configured keep their defaults
+type Thresholds struct {
+	kinds map[string]PanelThreshold
+}
+
+func AvailableThemeRoles() []string {
+	return sortedKeys(defaultThemeColors)
+}
+
+func AvailablePanelKinds() []string {
+	return sortedKeys(defaultThresholds)
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func NewTheme(colors map[string]string) (Theme, error) {
+	for role := range colors {
+		if _, ok := defaultThemeColors[role]; !ok {
+			return Theme{}, fmt.Errorf("'%s' is not a valid role of the theme, choose between %v", role, AvailableThemeRoles())
+		}
+	}
+
+	return Theme{colors: colors}, nil
+}
+
+func (t Theme) Color(role string) string {
+	if color, ok := t.colors[role]; ok && color != "" {
+		return color
+	}
+
+	return defaultThemeColors[role]
+}
+
+func NewThresholds(kinds map[string]PanelThreshold) (Thresholds, error) {
+	for kind := range kinds {
+		if _, ok := defaultThresholds[kind]; !ok {
+			return Thresholds{}, fmt.Errorf("'%s' is not a valid panel kind for thresholds, choose between %v", kind, AvailablePanelKinds())
+		}
+	}
+
+	return Thresholds{kinds: kinds}, nil
+}
+
+func (t Thresholds) Get(kind string) PanelThreshold {
+	if threshold, ok := t.kinds[kind]; ok {
+		return threshold
+	}
+
+	return defaultThresholds[kind]
+}
+
+// lines returns the thresholds a time series of the kind draws. Only configured kinds are drawn, besides the service
+// utilization which always had its line at 100%.
+func (t Thresholds) lines(kind string) (PanelThreshold, bool) {
+	if threshold, ok := t.kinds[kind]; ok {
+		return threshold, true
+	}
+
+	return defaultThresholds[kind], kind == PanelKindServiceUtilization
+}
+
+// newPanelThresholds renders the thresholds of the panel kind in the colors of the theme
+func newPanelThresholds(settings PanelSettings, kind string) PanelFieldConfigDefaultsThresholds {
+	return newPanelThresholdSteps(settings, kind, settings.thresholds.Get(kind))
+}
+
+// newPanelThresholdLines renders the thresholds a time series of the panel kind draws, see newPanelThresholdsStyle
+func newPanelThresholdLines(settings PanelSettings, kind string) PanelFieldConfigDefaultsThresholds {
+	threshold, ok := settings.thresholds.lines(kind)
+	if !ok {
+		return PanelFieldConfigDefaultsThresholds{}
+	}
+
+	return newPanelThresholdSteps(settings, kind, threshold)
+}
+
+func newPanelThresholdSteps(settings PanelSettings, kind string, threshold PanelThreshold) PanelFieldConfigDefaultsThresholds {
+	okRole, criticalRole := ThemeRoleThresholdOk, ThemeRoleThresholdCritical
+	if kind == PanelKindServiceUtilization {
+		okRole, criticalRole = ThemeRoleUtilizationOk, ThemeRoleUtilizationCritical
+	}
+
+	steps := []PanelFieldConfigDefaultsThresholdsStep{
+		{Color: settings.theme.Color(okRole)},
+	}
+
+	if threshold.Warning > 0 {
+		steps = append(steps, PanelFieldConfigDefaultsThresholdsStep{Color: settings.theme.Color(ThemeRoleThresholdWarning), Value: threshold.Warning})
+	}
+
+	if threshold.Critical > 0 {
+		steps = append(steps, PanelFieldConfigDefaultsThresholdsStep{Color: settings.theme.Color(criticalRole), Value: threshold.Critical})
+	}
+
+	return PanelFieldConfigDefaultsThresholds{
+		Mode:  "absolute",
+		Steps: steps,
+	}
+}
+
+// newPanelThresholdsStyle draws the thresholds of the panel kind as lines into a time series, if there are any
+func newPanelThresholdsStyle(settings PanelSettings, kind string) ThresholdsStyle {
+	threshold, ok := settings.thresholds.lines(kind)
+	if !ok {
+		return ThresholdsStyle{}
+	}
+
+	if threshold.Warning <= 0 && threshold.Critical <= 0 {
+		return ThresholdsStyle{Mode: "off"}
+	}
+
+	return ThresholdsStyle{Mode: "line"}
+}
@@ -0,0 +1,160 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTheme(t *testing.T) {
+	theme, err := builder.NewTheme(map[string]string{
+		builder.ThemeRoleError: "purple",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "purple", theme.Color(builder.ThemeRoleError))
+	assert.Equal(t, "semi-dark-blue", theme.Color(builder.ThemeRoleRequests))
+	assert.Equal(t, "semi-dark-green", builder.Theme{}.Color(builder.ThemeRoleSuccess))
+
+	_, err = builder.NewTheme(map[string]string{"fancy": "purple"})
+	assert.ErrorContains(t, err, "'fancy' is not a valid role of the theme")
+
+	_, err = builder.NewThresholds(map[string]builder.PanelThreshold{"fancy": {}})
+	assert.ErrorContains(t, err, "'fancy' is not a valid panel kind for thresholds")
+}
+
+// TestThemeDefaultColors keeps the colors of the dashboards without a configured theme
+func TestThemeDefaultColors(t *testing.T) {
+	defaults := map[string]string{
+		builder.ThemeRoleAverage:             "light-orange",
+		builder.ThemeRoleClientError:         "semi-dark-orange",
+		builder.ThemeRoleCpu:                 "light-green",
+		builder.ThemeRoleCpuRequests:         "red",
+		builder.ThemeRoleDesired:             "orange",
+		builder.ThemeRoleError:               "dark-red",
+		builder.ThemeRoleLatency:             "semi-dark-blue",
+		builder.ThemeRoleLimits:              "dark-red",
+		builder.ThemeRoleMaximum:             "light-red",
+		builder.ThemeRoleMemory:              "light-orange",
+		builder.ThemeRoleMemoryRequests:      "semi-dark-red",
+		builder.ThemeRoleMinimum:             "light-green",
+		builder.ThemeRoleRedirect:            "semi-dark-yellow",
+		builder.ThemeRoleRequests:            "semi-dark-blue",
+		builder.ThemeRoleReservation:         "red",
+		builder.ThemeRoleResourceLimits:      "orange",
+		builder.ThemeRoleSuccess:             "semi-dark-green",
+		builder.ThemeRoleThresholdCritical:   "red",
+		builder.ThemeRoleThresholdOk:         "green",
+		builder.ThemeRoleThresholdWarning:    "orange",
+		builder.ThemeRoleTraffic:             "super-light-blue",
+		builder.ThemeRoleUtilizationCritical: "semi-dark-red",
+		builder.ThemeRoleUtilizationOk:       "super-light-green",
+		builder.ThemeRoleWarning:             "dark-yellow",
+	}
+
+	assert.Len(t, builder.AvailableThemeRoles(), len(defaults))
+
+	for _, role := range builder.AvailableThemeRoles() {
+		assert.Equal(t, defaults[role], builder.Theme{}.Color(role), role)
+	}
+
+	db := newDashboardBuilder(t, provideLayoutResourceNames(), "ecs")
+	db.AddServiceAndTask()
+	dashboard := db.Build("")
+
+	assert.Equal(t, "semi-dark-red", overrideColor(t, findPanel(t, dashboard, "Memory Utilization (app)"), "Requests"))
+	assert.Equal(t, "red", overrideColor(t, findPanel(t, dashboard, "CPU Utilization (app)"), "Requests"))
+
+	db = newDashboardBuilder(t, provideIngressResourceNames(), "kubernetes")
+	db.AddTraefikService()
+	dashboard = db.Build("")
+
+	assert.Equal(t, "semi-dark-blue", overrideColor(t, findPanel(t, dashboard, "Request Count"), "Requests"))
+	assert.Equal(t, "semi-dark-blue", overrideColor(t, findPanel(t, dashboard, "Response Time"), "Response Time"))
+}
+
+func TestThemeLatency(t *testing.T) {
+	theme, err := builder.NewTheme(map[string]string{
+		builder.ThemeRoleLatency: "purple",
+	})
+	assert.NoError(t, err)
+
+	for _, ingress := range []string{"traefik", "nginx"} {
+		db := newDashboardBuilder(t, provideIngressResourceNames(), "kubernetes", builder.WithTheme(theme), builder.WithIngress(ingress))
+		db.AddTraefikService()
+		dashboard := db.Build("")
+
+		assert.Equal(t, "purple", overrideColor(t, findPanel(t, dashboard, "Response Time"), "Response Time"), ingress)
+		assert.Equal(t, "semi-dark-blue", overrideColor(t, findPanel(t, dashboard, "Request Count"), "Requests"), ingress)
+	}
+}
+
+func overrideColor(t *testing.T, panel builder.Panel, metric string) string {
+	for _, override := range panel.FieldConfig.Overrides {
+		if override.Matcher.Options != metric {
+			continue
+		}
+
+		for _, property := range override.Properties {
+			if property.Id == "color" {
+				return property.Value.FixedColor
+			}
+		}
+	}
+
+	t.Fatalf("there is no color override for %s in the panel %s", metric, panel.Title)
+
+	return ""
+}
+
+func TestThemeAndThresholdsPanels(t *testing.T) {
+	theme, err := builder.NewTheme(map[string]string{
+		builder.ThemeRoleThresholdWarning: "yellow",
+		builder.ThemeRoleError:            "red",
+	})
+	assert.NoError(t, err)
+
+	thresholds, err := builder.NewThresholds(map[string]builder.PanelThreshold{
+		builder.PanelKindSqsMessagesVisible: {Warning: 100, Critical: 1000},
+		builder.PanelKindKinsumerLag:        {},
+	})
+	assert.NoError(t, err)
+
+	db := newDashboardBuilder(t, provideLayoutResourceNames(), "ecs", builder.WithTheme(theme), builder.WithThresholds(thresholds))
+	db.AddCloudAwsSqsQueue(builder.MetadataCloudAwsSqsQueue{QueueNameFull: "prj-env-fam-grp-app-orders"})
+	db.AddStreamConsumer(builder.MetadataStreamConsumer{Name: "consumer"})
+	db.AddCloudAwsKinesisKinsumer(builder.MetadataCloudAwsKinesisKinsumer{StreamNameFull: "prj-env-fam-grp-events"})
+	dashboard := db.Build("")
+
+	messagesVisible := findPanel(t, dashboard, "Messages In Queue")
+	assert.Equal(t, "line", messagesVisible.FieldConfig.Defaults.Custom.ThresholdsStyle.Mode)
+	assert.Equal(t, []builder.PanelFieldConfigDefaultsThresholdsStep{
+		{Color: "green"},
+		{Color: "yellow", Value: 100},
+		{Color: "red", Value: 1000},
+	}, messagesVisible.FieldConfig.Defaults.Thresholds.Steps)
+
+	// configuring a panel kind without values disables its default thresholds
+	lag := findPanel(t, dashboard, "MillisecondsBehind")
+	assert.Equal(t, "off", lag.FieldConfig.Defaults.Custom.ThresholdsStyle.Mode)
+	assert.Len(t, lag.FieldConfig.Defaults.Thresholds.Steps, 1)
+
+	processed := findPanel(t, dashboard, "Processed Count and Errors")
+	assert.Contains(t, processed.FieldConfig.Overrides, builder.NewColorPropertyOverride("Error", "red", ""))
+
+	// time series of kinds which aren't configured keep drawing no thresholds at all
+	dashboard = newDashboardBuilder(t, provideLayoutResourceNames(), "ecs").Build("")
+	_ = dashboard
+}
+
+func findPanel(t *testing.T, dashboard builder.Dashboard, title string) builder.Panel {
+	for _, panel := range dashboard.Panels {
+		if panel.Title == title {
+			return panel
+		}
+	}
+
+	t.Fatalf("there is no panel with the title %s", title)
+
+	return builder.Panel{}
+}
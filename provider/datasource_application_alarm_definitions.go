@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+)
+
+type ApplicationAlarmDefinitionsData struct {
+	Project     types.String `tfsdk:"project"`
+	Environment types.String `tfsdk:"environment"`
+	Family      types.String `tfsdk:"family"`
+	Group       types.String `tfsdk:"group"`
+	Application types.String `tfsdk:"application"`
+	Thresholds  types.Object `tfsdk:"thresholds"`
+	RoutePaths  types.Object `tfsdk:"route_paths"`
+	Alarms      types.List   `tfsdk:"alarms"`
+}
+
+func (d ApplicationAlarmDefinitionsData) AppId() builder.AppId {
+	return builder.AppId{
+		Project:     d.Project.Value,
+		Environment: d.Environment.Value,
+		Family:      d.Family.Value,
+		Group:       d.Group.Value,
+		Application: d.Application.Value,
+	}
+}
+
+type alarmSettingsData struct {
+	Enabled           types.Bool    `tfsdk:"enabled"`
+	Threshold         types.Float64 `tfsdk:"threshold"`
+	Period            types.Int64   `tfsdk:"period"`
+	EvaluationPeriods types.Int64   `tfsdk:"evaluation_periods"`
+}
+
+type ApplicationAlarmDefinitionsDatasourceType struct{}
+
+func (a *ApplicationAlarmDefinitionsDatasourceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	thresholdAttributes := map[string]tfsdk.Attribute{}
+	for _, resource := range builder.AvailableAlarmResources() {
+		defaults := builder.DefaultAlarmSettings(resource)
+
+		thresholdAttributes[resource] = tfsdk.Attribute{
+			Optional:            true,
+			MarkdownDescription: fmt.Sprintf("Alarms of the %s resources, defaults to a threshold of %v over %d periods of %d seconds", resource, defaults.Threshold, defaults.EvaluationPeriods, defaults.Period),
+			Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+				"enabled": {
+					Type:                types.BoolType,
+					Optional:            true,
+					MarkdownDescription: `Set to false to skip the alarms of the resource type`,
+				},
+				"threshold": {
+					Type:     types.Float64Type,
+					Optional: true,
+				},
+				"period": {
+					Type:     types.Int64Type,
+					Optional: true,
+				},
+				"evaluation_periods": {
+					Type:     types.Int64Type,
+					Optional: true,
+				},
+			}),
+		}
+	}
+
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"project": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"environment": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"family": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"group": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"application": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"thresholds": {
+				Optional:            true,
+				MarkdownDescription: `Changes the thresholds of the alarms per resource type, values which aren't set keep their default`,
+				Attributes:          tfsdk.SingleNestedAttributes(thresholdAttributes),
+			},
+			"route_paths": {
+				Optional:            true,
+				MarkdownDescription: `Filters the http routes which get alarms by their path. Excludes "^/health$" unless exclude is set`,
+				Attributes:          nameFilterAttributes(),
+			},
+			"alarms": {
+				Type: types.ListType{
+					ElemType: types.ObjectType{
+						AttrTypes: builder.AlarmDefinitionAttrTypes(),
+					},
+				},
+				Computed:            true,
+				MarkdownDescription: `Alarms for the sqs queues, kinsumers, dynamodb tables, stream consumers and http routes of the application, which can be passed to aws_cloudwatch_metric_alarm with for_each = { for alarm in alarms : alarm.name => alarm }`,
+			},
+		},
+	}, nil
+}
+
+func (a *ApplicationAlarmDefinitionsDatasourceType) NewDataSource(_ context.Context, provider tfsdk.Provider) (tfsdk.DataSource, diag.Diagnostics) {
+	return &ApplicationAlarmDefinitionsDatasource{
+		metadataReader:       provider.(*GosolineProvider).metadataReader,
+		resourceNamePatterns: provider.(*GosolineProvider).resourceNamePatterns,
+	}, nil
+}
+
+type ApplicationAlarmDefinitionsDatasource struct {
+	metadataReader       *builder.MetadataReader
+	resourceNamePatterns ResourceNamePatterns
+}
+
+func (a *ApplicationAlarmDefinitionsDatasource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest, response *tfsdk.ReadDataSourceResponse) {
+	state := &ApplicationAlarmDefinitionsData{}
+
+	diags := request.Config.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var err error
+	var metadata *builder.MetadataApplication
+
+	if metadata, err = a.metadataReader.ReadMetadata(state.AppId()); err != nil {
+		response.Diagnostics.AddError("can not get metadata", err.Error())
+
+		return
+	}
+
+	settings, err := a.getAlarmSettings(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid thresholds", err.Error())
+
+		return
+	}
+
+	filter, err := a.getFilter(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid route paths", err.Error())
+
+		return
+	}
+
+	resourceNames := &builder.ResourceNames{
+		CloudwatchNamespace: builder.Augment(a.resourceNamePatterns.CloudwatchNamespace, state.AppId()),
+		Environment:         state.Environment.Value,
+	}
+
+	ab, err := builder.NewAlarmBuilder(resourceNames, state.AppId(), settings, filter)
+	if err != nil {
+		response.Diagnostics.AddError("invalid thresholds", err.Error())
+
+		return
+	}
+
+	for _, queue := range metadata.Cloud.Aws.Sqs.Queues {
+		ab.AddCloudAwsSqsQueue(queue)
+	}
+
+	for _, kinsumer := range metadata.Cloud.Aws.Kinesis.Kinsumers {
+		ab.AddCloudAwsKinesisKinsumer(kinsumer)
+	}
+
+	for _, table := range metadata.Cloud.Aws.Dynamodb.Tables {
+		ab.AddDynamoDbTable(table)
+	}
+
+	for _, consumer := range metadata.Stream.Consumers {
+		ab.AddStreamConsumer(consumer)
+	}
+
+	for _, server := range metadata.HttpServers {
+		for _, handler := range server.Handlers {
+			ab.AddHttpServerHandler(server.Name, handler)
+		}
+	}
+
+	state.Alarms = ab.Build().ToValue()
+
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// getAlarmSettings returns the settings of the configured resource types, completed with the defaults for the values
+// which aren't set
+func (a *ApplicationAlarmDefinitionsDatasource) getAlarmSettings(ctx context.Context, state *ApplicationAlarmDefinitionsData) (map[string]builder.AlarmSettings, error) {
+	settings := make(map[string]builder.AlarmSettings)
+
+	for resource, value := range state.Thresholds.Attrs {
+		object, ok := value.(types.Object)
+		if !ok || object.IsNull() {
+			continue
+		}
+
+		var data alarmSettingsData
+		if diags := object.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+			return nil, fmt.Errorf("failed to convert thresholds.%s attribute to native type: %v", resource, diags)
+		}
+
+		resourceSettings := builder.DefaultAlarmSettings(resource)
+
+		if !data.Enabled.IsNull() {
+			resourceSettings.Enabled = data.Enabled.Value
+		}
+
+		if !data.Threshold.IsNull() {
+			resourceSettings.Threshold = data.Threshold.Value
+		}
+
+		if !data.Period.IsNull() {
+			resourceSettings.Period = int(data.Period.Value)
+		}
+
+		if !data.EvaluationPeriods.IsNull() {
+			resourceSettings.EvaluationPeriods = int(data.EvaluationPeriods.Value)
+		}
+
+		settings[resource] = resourceSettings
+	}
+
+	return settings, nil
+}
+
+// getFilter returns the filter of the routes which get alarms, like on the dashboards the health check is excluded by default
+func (a *ApplicationAlarmDefinitionsDatasource) getFilter(ctx context.Context, state *ApplicationAlarmDefinitionsData) (*builder.DashboardFilter, error) {
+	settings := builder.DashboardFilterSettings{
+		RoutePaths: builder.NameFilterSettings{
+			Exclude: defaultRoutePathExcludes,
+		},
+	}
+
+	if err := getNameFilterSettings(ctx, "route_paths", state.RoutePaths, &settings.RoutePaths); err != nil {
+		return nil, err
+	}
+
+	return builder.NewDashboardFilter(settings)
+}
@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func provideAppIdValues() map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"project":     tftypes.NewValue(tftypes.String, "prj"),
+		"environment": tftypes.NewValue(tftypes.String, "env"),
+		"family":      tftypes.NewValue(tftypes.String, "fam"),
+		"group":       tftypes.NewValue(tftypes.String, "grp"),
+		"application": tftypes.NewValue(tftypes.String, "app"),
+	}
+}
+
+// provideConfig builds the config of the data source from its schema, attributes which aren't given are null
+func provideConfig(t *testing.T, dataSourceType tfsdk.DataSourceType, values map[string]tftypes.Value) tfsdk.Config {
+	schema, diags := dataSourceType.GetSchema(context.Background())
+	require.False(t, diags.HasError(), "%v", diags)
+
+	return tfsdk.Config{
+		Schema: schema,
+		Raw:    newObjectValue(schema.TerraformType(context.Background()).(tftypes.Object), values),
+	}
+}
+
+func newObjectValue(objectType tftypes.Object, values map[string]tftypes.Value) tftypes.Value {
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+
+			continue
+		}
+
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	return tftypes.NewValue(objectType, attributes)
+}
+
+func attributeType(config tfsdk.Config, names ...string) tftypes.Type {
+	typ := config.Schema.TerraformType(context.Background())
+
+	for _, name := range names {
+		typ = typ.(tftypes.Object).AttributeTypes[name]
+	}
+
+	return typ
+}
+
+func newStringList(values ...string) tftypes.Value {
+	elements := make([]tftypes.Value, len(values))
+	for i, value := range values {
+		elements[i] = tftypes.NewValue(tftypes.String, value)
+	}
+
+	return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elements)
+}
+
+func readDataSource(t *testing.T, dataSource tfsdk.DataSource, config tfsdk.Config) tfsdk.State {
+	response := &tfsdk.ReadDataSourceResponse{
+		State: tfsdk.State{
+			Schema: config.Schema,
+		},
+	}
+
+	dataSource.Read(context.Background(), tfsdk.ReadDataSourceRequest{Config: config}, response)
+	require.False(t, response.Diagnostics.HasError(), "%v", response.Diagnostics)
+
+	return response.State
+}
+
+func provideAlarmConfig(t *testing.T, thresholds map[string]map[string]tftypes.Value, routePaths map[string]tftypes.Value) tfsdk.Config {
+	values := provideAppIdValues()
+	config := provideConfig(t, &ApplicationAlarmDefinitionsDatasourceType{}, values)
+
+	if thresholds != nil {
+		resources := make(map[string]tftypes.Value, len(thresholds))
+		for resource, settings := range thresholds {
+			resources[resource] = newObjectValue(attributeType(config, "thresholds", resource).(tftypes.Object), settings)
+		}
+
+		values["thresholds"] = newObjectValue(attributeType(config, "thresholds").(tftypes.Object), resources)
+	}
+
+	if routePaths != nil {
+		values["route_paths"] = newObjectValue(attributeType(config, "route_paths").(tftypes.Object), routePaths)
+	}
+
+	return provideConfig(t, &ApplicationAlarmDefinitionsDatasourceType{}, values)
+}
+
+func provideAlarmDefinitionsDatasource(t *testing.T) *ApplicationAlarmDefinitionsDatasource {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		_, err := writer.Write([]byte(`{
+			"cloud": {"aws": {"sqs": {"queues": [{"queue_name": "orders", "queue_name_full": "prj-env-fam-grp-app-orders"}]}}},
+			"httpservers": [{"name": "default", "handlers": [{"method": "GET", "path": "/health"}, {"method": "GET", "path": "/v1/users"}]}]
+		}`))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(ts.Close)
+
+	return &ApplicationAlarmDefinitionsDatasource{
+		metadataReader: builder.NewMetadataReaderWithHostBuilder(func(_ builder.AppId) string {
+			return ts.URL
+		}),
+		resourceNamePatterns: ResourceNamePatterns{
+			CloudwatchNamespace: defaultCloudwatchNamespaceNamePattern,
+		},
+	}
+}
+
+func readAlarms(t *testing.T, state tfsdk.State) map[string]builder.AlarmDefinition {
+	data := ApplicationAlarmDefinitionsData{}
+	require.False(t, state.Get(context.Background(), &data).HasError())
+
+	alarms := make(map[string]builder.AlarmDefinition)
+
+	for _, element := range data.Alarms.Elems {
+		attrs := element.(types.Object).Attrs
+
+		alarms[attrs["name"].(types.String).Value] = builder.AlarmDefinition{
+			Period:            int(attrs["period"].(types.Int64).Value),
+			EvaluationPeriods: int(attrs["evaluation_periods"].(types.Int64).Value),
+			Threshold:         attrs["threshold"].(types.Float64).Value,
+		}
+	}
+
+	return alarms
+}
+
+func TestApplicationAlarmDefinitionsSchema(t *testing.T) {
+	schema, diags := (&ApplicationAlarmDefinitionsDatasourceType{}).GetSchema(context.Background())
+	assert.False(t, diags.HasError())
+
+	thresholds := schema.Attributes["thresholds"].Attributes.GetAttributes()
+	for _, resource := range builder.AvailableAlarmResources() {
+		assert.Contains(t, thresholds, resource)
+	}
+
+	assert.True(t, schema.Attributes["alarms"].Computed)
+}
+
+func TestApplicationAlarmDefinitionsRead(t *testing.T) {
+	config := provideAlarmConfig(t, nil, nil)
+	alarms := readAlarms(t, readDataSource(t, provideAlarmDefinitionsDatasource(t), config))
+
+	// the health check is excluded by default
+	assert.Equal(t, map[string]builder.AlarmDefinition{
+		"prj-env-fam-grp-app-http_route-default-GET-/v1/users-5xx": {Period: 300, EvaluationPeriods: 1, Threshold: 10},
+		"prj-env-fam-grp-app-sqs-orders-messages-visible":          {Period: 300, EvaluationPeriods: 3, Threshold: 1000},
+	}, alarms)
+}
+
+func TestApplicationAlarmDefinitionsReadThresholds(t *testing.T) {
+	config := provideAlarmConfig(t, map[string]map[string]tftypes.Value{
+		builder.AlarmResourceSqs: {
+			"threshold": tftypes.NewValue(tftypes.Number, 50),
+		},
+		builder.AlarmResourceHttpRoute: {
+			"period":             tftypes.NewValue(tftypes.Number, 60),
+			"evaluation_periods": tftypes.NewValue(tftypes.Number, 5),
+		},
+	}, map[string]tftypes.Value{
+		"exclude": newStringList("^/v1/"),
+	})
+
+	alarms := readAlarms(t, readDataSource(t, provideAlarmDefinitionsDatasource(t), config))
+
+	// the configured values are merged over the defaults of the resource type and an exclude replaces the health check
+	assert.Equal(t, map[string]builder.AlarmDefinition{
+		"prj-env-fam-grp-app-http_route-default-GET-/health-5xx": {Period: 60, EvaluationPeriods: 5, Threshold: 10},
+		"prj-env-fam-grp-app-sqs-orders-messages-visible":        {Period: 300, EvaluationPeriods: 3, Threshold: 50},
+	}, alarms)
+}
+
+func TestApplicationAlarmDefinitionsGetAlarmSettings(t *testing.T) {
+	config := provideAlarmConfig(t, map[string]map[string]tftypes.Value{
+		builder.AlarmResourceDdb: {
+			"enabled": tftypes.NewValue(tftypes.Bool, false),
+		},
+	}, nil)
+
+	state := &ApplicationAlarmDefinitionsData{}
+	require.False(t, config.Get(context.Background(), state).HasError())
+
+	settings, err := (&ApplicationAlarmDefinitionsDatasource{}).getAlarmSettings(context.Background(), state)
+	assert.NoError(t, err)
+
+	disabled := builder.DefaultAlarmSettings(builder.AlarmResourceDdb)
+	disabled.Enabled = false
+
+	// resource types which aren't configured are left to the defaults of the alarm builder
+	assert.Equal(t, map[string]builder.AlarmSettings{
+		builder.AlarmResourceDdb: disabled,
+	}, settings)
+}
@@ -2,7 +2,8 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"fmt"
 	"sort"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -12,14 +13,33 @@ import (
 )
 
 type ApplicationDashboardDefinitionData struct {
-	Project     types.String `tfsdk:"project"`
-	Environment types.String `tfsdk:"environment"`
-	Family      types.String `tfsdk:"family"`
-	Group       types.String `tfsdk:"group"`
-	Application types.String `tfsdk:"application"`
-	Containers  types.List   `tfsdk:"containers"`
-	Title       types.String `tfsdk:"title"`
-	Body        types.String `tfsdk:"body"`
+	Project           types.String `tfsdk:"project"`
+	Environment       types.String `tfsdk:"environment"`
+	Family            types.String `tfsdk:"family"`
+	Group             types.String `tfsdk:"group"`
+	Application       types.String `tfsdk:"application"`
+	Containers        types.List   `tfsdk:"containers"`
+	ExcludeContainers types.List   `tfsdk:"exclude_containers"`
+	Orchestrator      types.String `tfsdk:"orchestrator"`
+	WorkloadKind      types.String `tfsdk:"workload_kind"`
+	Collapse          types.Object `tfsdk:"collapse"`
+	Templating        types.Bool   `tfsdk:"templating"`
+	Annotations       types.Object `tfsdk:"annotations"`
+	Uid               types.String `tfsdk:"uid"`
+	Tags              types.List   `tfsdk:"tags"`
+	Refresh           types.String `tfsdk:"refresh"`
+	Time              types.Object `tfsdk:"time"`
+	Timezone          types.String `tfsdk:"timezone"`
+	GraphTooltip      types.String `tfsdk:"graph_tooltip"`
+	Links             types.List   `tfsdk:"links"`
+	ExtraSections     types.List   `tfsdk:"extra_sections"`
+	Filter            types.Object `tfsdk:"filter"`
+	HttpAggregate     types.Object `tfsdk:"http_aggregate"`
+	Percentiles       types.List   `tfsdk:"percentiles"`
+	Layout            types.Object `tfsdk:"layout"`
+	Title             types.String `tfsdk:"title"`
+	Body              types.String `tfsdk:"body"`
+	BodySha256        types.String `tfsdk:"body_sha256"`
 }
 
 func (d ApplicationDashboardDefinitionData) AppId() builder.AppId {
@@ -32,6 +52,109 @@ func (d ApplicationDashboardDefinitionData) AppId() builder.AppId {
 	}
 }
 
+type collapseData struct {
+	All        types.Bool `tfsdk:"all"`
+	HttpRoutes types.Bool `tfsdk:"http_routes"`
+	Kinesis    types.Bool `tfsdk:"kinesis"`
+	Sqs        types.Bool `tfsdk:"sqs"`
+	Sns        types.Bool `tfsdk:"sns"`
+	Ddb        types.Bool `tfsdk:"ddb"`
+}
+
+type annotationsData struct {
+	Deployments types.Bool   `tfsdk:"deployments"`
+	IconColor   types.String `tfsdk:"icon_color"`
+}
+
+type timeData struct {
+	From types.String `tfsdk:"from"`
+	To   types.String `tfsdk:"to"`
+}
+
+type linkData struct {
+	Title       types.String `tfsdk:"title"`
+	Type        types.String `tfsdk:"type"`
+	Url         types.String `tfsdk:"url"`
+	Tags        types.List   `tfsdk:"tags"`
+	Icon        types.String `tfsdk:"icon"`
+	Tooltip     types.String `tfsdk:"tooltip"`
+	AsDropdown  types.Bool   `tfsdk:"as_dropdown"`
+	IncludeVars types.Bool   `tfsdk:"include_vars"`
+	KeepTime    types.Bool   `tfsdk:"keep_time"`
+	TargetBlank types.Bool   `tfsdk:"target_blank"`
+}
+
+type extraSectionData struct {
+	Title     types.String `tfsdk:"title"`
+	Position  types.String `tfsdk:"position"`
+	Collapsed types.Bool   `tfsdk:"collapsed"`
+	Panels    types.List   `tfsdk:"panels"`
+}
+
+type extraPanelData struct {
+	RawJson    types.String `tfsdk:"raw_json"`
+	Datasource types.String `tfsdk:"datasource"`
+	Queries    types.List   `tfsdk:"queries"`
+	Title      types.String `tfsdk:"title"`
+	Type       types.String `tfsdk:"type"`
+	Unit       types.String `tfsdk:"unit"`
+}
+
+type filterData struct {
+	IncludeSections types.List   `tfsdk:"include_sections"`
+	ExcludeSections types.List   `tfsdk:"exclude_sections"`
+	RoutePaths      types.Object `tfsdk:"route_paths"`
+	RouteMethods    types.Object `tfsdk:"route_methods"`
+	QueueNames      types.Object `tfsdk:"queue_names"`
+	TableNames      types.Object `tfsdk:"table_names"`
+	StreamNames     types.Object `tfsdk:"stream_names"`
+	TopicNames      types.Object `tfsdk:"topic_names"`
+}
+
+type nameFilterData struct {
+	Include types.List `tfsdk:"include"`
+	Exclude types.List `tfsdk:"exclude"`
+}
+
+type httpAggregateData struct {
+	TopRoutes     types.Int64  `tfsdk:"top_routes"`
+	RouteSections types.Bool   `tfsdk:"route_sections"`
+	RoutePaths    types.Object `tfsdk:"route_paths"`
+}
+
+type panelSizeData struct {
+	PanelWidth   types.Int64 `tfsdk:"panel_width"`
+	PanelHeight  types.Int64 `tfsdk:"panel_height"`
+	PanelsPerRow types.Int64 `tfsdk:"panels_per_row"`
+}
+
+type layoutData struct {
+	PanelWidth   types.Int64 `tfsdk:"panel_width"`
+	PanelHeight  types.Int64 `tfsdk:"panel_height"`
+	PanelsPerRow types.Int64 `tfsdk:"panels_per_row"`
+	Sections     types.Map   `tfsdk:"sections"`
+}
+
+// defaultRoutePathExcludes are skipped unless the route paths to exclude are configured explicitly
+var defaultRoutePathExcludes = []string{"^/health$"}
+
+var graphTooltips = map[string]int{
+	"default":          builder.GraphTooltipDefault,
+	"shared_crosshair": builder.GraphTooltipSharedCrosshair,
+	"shared_tooltip":   builder.GraphTooltipSharedTooltip,
+}
+
+func availableGraphTooltips() []string {
+	names := make([]string, 0, len(graphTooltips))
+	for name := range graphTooltips {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
 type ApplicationDashboardDefinitionDatasourceType struct{}
 
 func (a *ApplicationDashboardDefinitionDatasourceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
@@ -58,33 +181,376 @@ func (a *ApplicationDashboardDefinitionDatasourceType) GetSchema(_ context.Conte
 				Required: true,
 			},
 			"containers": {
-				Type:     types.ListType{ElemType: types.StringType},
-				Required: true,
+				Type:                types.ListType{ElemType: types.StringType},
+				Optional:            true,
+				MarkdownDescription: `The containers to render resource usage panels for. If omitted, they are discovered from the ecs task definition or, if the provider has a kubernetes configuration, from the pod template of the workload`,
+			},
+			"exclude_containers": {
+				Type:                types.ListType{ElemType: types.StringType},
+				Optional:            true,
+				MarkdownDescription: `Regular expressions for container names to skip when the containers are discovered, e.g. ["^log_router$", "^datadog-agent$"]`,
+			},
+			"orchestrator": {
+				Type:                types.StringType,
+				Optional:            true,
+				MarkdownDescription: `Overrides the orchestrator of the provider for this dashboard, e.g. "ecs_fargate" to render the resource usage from container insights`,
+			},
+			"workload_kind": {
+				Type:                types.StringType,
+				Optional:            true,
+				MarkdownDescription: `Kind of the kubernetes workload: "deployment", "statefulset", "daemonset" or "rollout" (argo rollouts). If omitted, it is discovered if the provider has a kubernetes configuration, otherwise a deployment is assumed`,
+			},
+			"collapse": {
+				Optional:            true,
+				MarkdownDescription: `Renders the rows of the given sections collapsed, their panels are only loaded once the row is expanded`,
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					builder.DashboardSectionAll: {
+						Type:                types.BoolType,
+						Optional:            true,
+						MarkdownDescription: `Collapses every row of the dashboard`,
+					},
+					builder.DashboardSectionHttpRoutes: {
+						Type:                types.BoolType,
+						Optional:            true,
+						MarkdownDescription: `Collapses the rows of the http server routes`,
+					},
+					builder.DashboardSectionKinesis: {
+						Type:                types.BoolType,
+						Optional:            true,
+						MarkdownDescription: `Collapses the rows of the kinsumers, record writers and kinesis streams`,
+					},
+					builder.DashboardSectionSqs: {
+						Type:                types.BoolType,
+						Optional:            true,
+						MarkdownDescription: `Collapses the rows of the sqs queues`,
+					},
+					builder.DashboardSectionSns: {
+						Type:                types.BoolType,
+						Optional:            true,
+						MarkdownDescription: `Collapses the rows of the sns topics`,
+					},
+					builder.DashboardSectionDdb: {
+						Type:                types.BoolType,
+						Optional:            true,
+						MarkdownDescription: `Collapses the rows of the dynamodb tables`,
+					},
+				}),
+			},
+			"templating": {
+				Type:                types.BoolType,
+				Optional:            true,
+				MarkdownDescription: `Adds template variables for the datasources, containers, http routes and the interval. The container and route panels are repeated for the selected values instead of being rendered once per container and route`,
+			},
+			"annotations": {
+				Optional:            true,
+				MarkdownDescription: `Configures the annotations of the dashboard`,
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"deployments": {
+						Type:                types.BoolType,
+						Optional:            true,
+						MarkdownDescription: `Marks the deployments of the application: new task definition revisions on ecs or changes of the workload on kubernetes. Defaults to true`,
+					},
+					"icon_color": {
+						Type:                types.StringType,
+						Optional:            true,
+						MarkdownDescription: `Color of the annotations, e.g. "#5794F2"`,
+					},
+				}),
 			},
 			"title": {
 				Type:     types.StringType,
 				Optional: true,
 			},
+			"uid": {
+				Type:                types.StringType,
+				Optional:            true,
+				MarkdownDescription: `Uid of the dashboard, defaults to "{project}-{env}-{family}-{group}-{app}" which is shortened and suffixed with a hash if it exceeds 40 characters`,
+			},
+			"tags": {
+				Type:                types.ListType{ElemType: types.StringType},
+				Optional:            true,
+				MarkdownDescription: `Tags of the dashboard, defaults to the project, environment, family, group and application`,
+			},
+			"refresh": {
+				Type:                types.StringType,
+				Optional:            true,
+				MarkdownDescription: `Auto refresh interval of the dashboard, e.g. "1m". Auto refresh is disabled by default`,
+			},
+			"time": {
+				Optional:            true,
+				MarkdownDescription: `Default time range of the dashboard, defaults to the last 3 hours`,
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"from": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"to": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+				}),
+			},
+			"timezone": {
+				Type:                types.StringType,
+				Optional:            true,
+				MarkdownDescription: `Timezone of the dashboard: "browser", "utc" or an IANA timezone. Defaults to "browser"`,
+			},
+			"graph_tooltip": {
+				Type:                types.StringType,
+				Optional:            true,
+				MarkdownDescription: `Tooltip behaviour across panels: "default", "shared_crosshair" or "shared_tooltip". Defaults to "shared_crosshair"`,
+			},
+			"extra_sections": {
+				Optional:            true,
+				MarkdownDescription: `Additional sections with user supplied panels. The placeholders {project}, {env}, {family}, {group} and {app} are replaced in the titles, queries and raw json`,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"title": {
+						Type:                types.StringType,
+						Required:            true,
+						MarkdownDescription: `Title of the row of the section`,
+					},
+					"position": {
+						Type:                types.StringType,
+						Optional:            true,
+						MarkdownDescription: `Where to place the section: "top", "after_resource_usage", "after_errors", "after_http_servers" or "bottom". Defaults to "bottom"`,
+					},
+					"collapsed": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"panels": {
+						Required: true,
+						Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+							"raw_json": {
+								Type:                types.StringType,
+								Optional:            true,
+								MarkdownDescription: `The json of a grafana panel, e.g. from jsonencode. Only its id and position are managed, the other attributes of the panel spec are ignored`,
+							},
+							"datasource": {
+								Type:                types.StringType,
+								Optional:            true,
+								MarkdownDescription: `"prometheus", "cloudwatch" for metric math expressions or the name of a prometheus compatible datasource. Defaults to "prometheus"`,
+							},
+							"queries": {
+								Type:     types.ListType{ElemType: types.StringType},
+								Optional: true,
+							},
+							"title": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+							"type": {
+								Type:                types.StringType,
+								Optional:            true,
+								MarkdownDescription: `Type of the panel, defaults to "timeseries"`,
+							},
+							"unit": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+						}),
+					},
+				}),
+			},
+			"filter": {
+				Optional:            true,
+				MarkdownDescription: `Restricts the dashboard to the matching sections, http routes and resources. The name filters are regular expressions, a name is kept if it matches any of the include patterns, or there are none, and none of the exclude patterns`,
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"include_sections": {
+						Type:                types.ListType{ElemType: types.StringType},
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("Only renders the given sections, choose between %v. All sections are rendered if omitted", builder.DashboardSections()),
+					},
+					"exclude_sections": {
+						Type:                types.ListType{ElemType: types.StringType},
+						Optional:            true,
+						MarkdownDescription: `Skips the given sections`,
+					},
+					"route_paths": {
+						Optional:            true,
+						MarkdownDescription: `Filters the http routes by their path. Excludes "^/health$" unless exclude is set`,
+						Attributes:          nameFilterAttributes(),
+					},
+					"route_methods": {
+						Optional:            true,
+						MarkdownDescription: `Filters the http routes by their method`,
+						Attributes:          nameFilterAttributes(),
+					},
+					"queue_names": {
+						Optional:            true,
+						MarkdownDescription: `Filters the sqs queues by their full name`,
+						Attributes:          nameFilterAttributes(),
+					},
+					"table_names": {
+						Optional:            true,
+						MarkdownDescription: `Filters the dynamodb tables by their name`,
+						Attributes:          nameFilterAttributes(),
+					},
+					"stream_names": {
+						Optional:            true,
+						MarkdownDescription: `Filters the kinsumers, record writers and kinesis streams by the full name of the stream`,
+						Attributes:          nameFilterAttributes(),
+					},
+					"topic_names": {
+						Optional:            true,
+						MarkdownDescription: `Filters the sns topics by their name`,
+						Attributes:          nameFilterAttributes(),
+					},
+				}),
+			},
+			"http_aggregate": {
+				Optional:            true,
+				MarkdownDescription: `Renders a single section per http server with the request count, response time and status codes over all routes and tables of the busiest and slowest routes, instead of a section per route`,
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"top_routes": {
+						Type:                types.Int64Type,
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("Number of routes in the tables of the busiest and slowest routes, defaults to %d", builder.DefaultHttpServerTopRoutes),
+					},
+					"route_sections": {
+						Type:                types.BoolType,
+						Optional:            true,
+						MarkdownDescription: `Keeps the sections per route next to the aggregate, limited to the routes matching route_paths`,
+					},
+					"route_paths": {
+						Optional:            true,
+						MarkdownDescription: `Filters the routes which keep a section of their own by their path`,
+						Attributes:          nameFilterAttributes(),
+					},
+				}),
+			},
+			"percentiles": {
+				Type:                types.ListType{ElemType: types.Float64Type},
+				Optional:            true,
+				MarkdownDescription: `Percentiles shown next to the average in the response time panels of the http routes, load balancers and ingresses, e.g. [50, 90, 99]`,
+			},
+			"layout": {
+				Optional:            true,
+				MarkdownDescription: fmt.Sprintf("Changes the size of the panels, which are %d wide and %d high by default on a grid %d wide", builder.PanelWidth, builder.PanelHeight, builder.DashboadWidth),
+				Attributes: tfsdk.SingleNestedAttributes(panelSizeAttributes(map[string]tfsdk.Attribute{
+					"sections": {
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("Overrides the size of the panels of single sections, keyed by the section: %v. Sizes which aren't set keep the size of the panels of the section", builder.DashboardSections()),
+						Attributes:          tfsdk.MapNestedAttributes(panelSizeAttributes(map[string]tfsdk.Attribute{})),
+					},
+				})),
+			},
+			"links": {
+				Optional:            true,
+				MarkdownDescription: `Links shown at the top of the dashboard`,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"title": {
+						Type:     types.StringType,
+						Required: true,
+					},
+					"type": {
+						Type:                types.StringType,
+						Optional:            true,
+						MarkdownDescription: `"link" to link to the url or "dashboards" to link to the dashboards with the given tags. Defaults to "link"`,
+					},
+					"url": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"tags": {
+						Type:     types.ListType{ElemType: types.StringType},
+						Optional: true,
+					},
+					"icon": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"tooltip": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"as_dropdown": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"include_vars": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"keep_time": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"target_blank": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+				}),
+			},
 			"body": {
 				Type:     types.StringType,
 				Computed: true,
 			},
+			"body_sha256": {
+				Type:                types.StringType,
+				Computed:            true,
+				MarkdownDescription: `Hex encoded sha256 of the body, it only changes if the dashboard changes`,
+			},
 		},
 	}, nil
 }
 
+func nameFilterAttributes() tfsdk.NestedAttributes {
+	return tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+		"include": {
+			Type:     types.ListType{ElemType: types.StringType},
+			Optional: true,
+		},
+		"exclude": {
+			Type:     types.ListType{ElemType: types.StringType},
+			Optional: true,
+		},
+	})
+}
+
+// panelSizeAttributes adds the attributes configuring the size of panels to the given attributes
+func panelSizeAttributes(attributes map[string]tfsdk.Attribute) map[string]tfsdk.Attribute {
+	attributes["panel_width"] = tfsdk.Attribute{
+		Type:     types.Int64Type,
+		Optional: true,
+	}
+	attributes["panel_height"] = tfsdk.Attribute{
+		Type:     types.Int64Type,
+		Optional: true,
+	}
+	attributes["panels_per_row"] = tfsdk.Attribute{
+		Type:                types.Int64Type,
+		Optional:            true,
+		MarkdownDescription: `Divides the width of the dashboard evenly between the given number of panels, takes precedence over panel_width`,
+	}
+
+	return attributes
+}
+
 func (a *ApplicationDashboardDefinitionDatasourceType) NewDataSource(_ context.Context, provider tfsdk.Provider) (tfsdk.DataSource, diag.Diagnostics) {
 	return &ApplicationDashboardDefinitionDataSource{
+		awsClients:           provider.(*GosolineProvider).awsClients,
+		kubernetesClient:     provider.(*GosolineProvider).kubernetesClient,
+		selectPodsByLabels:   provider.(*GosolineProvider).kubernetesSelectPodsByLabels,
 		metadataReader:       provider.(*GosolineProvider).metadataReader,
 		resourceNamePatterns: provider.(*GosolineProvider).resourceNamePatterns,
 		orchestrator:         provider.(*GosolineProvider).orchestrator,
+		ingress:              provider.(*GosolineProvider).ingress,
+		theme:                provider.(*GosolineProvider).theme,
+		thresholds:           provider.(*GosolineProvider).thresholds,
 	}, nil
 }
 
 type ApplicationDashboardDefinitionDataSource struct {
+	awsClients           *builder.AwsClients
+	kubernetesClient     *builder.KubernetesClient
+	selectPodsByLabels   bool
 	metadataReader       *builder.MetadataReader
 	resourceNamePatterns ResourceNamePatterns
 	orchestrator         string
+	ingress              string
+	theme                builder.Theme
+	thresholds           builder.Thresholds
 }
 
 func (a *ApplicationDashboardDefinitionDataSource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest, response *tfsdk.ReadDataSourceResponse) {
@@ -107,14 +573,106 @@ func (a *ApplicationDashboardDefinitionDataSource) Read(ctx context.Context, req
 		return
 	}
 
-	db := builder.NewDashboardBuilder(resourceNames, a.orchestrator)
+	collapsedSections, err := a.getCollapsedSections(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid collapse settings", err.Error())
+
+		return
+	}
+
+	annotations, err := a.getAnnotationSettings(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid annotations settings", err.Error())
+
+		return
+	}
+
+	dashboardSettings, err := a.getDashboardSettings(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid dashboard settings", err.Error())
+
+		return
+	}
+
+	filter, err := a.getFilter(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid filter", err.Error())
+
+		return
+	}
+
+	httpAggregate, err := a.getHttpAggregate(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid http aggregate settings", err.Error())
+
+		return
+	}
+
+	percentiles, err := a.getPercentiles(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid percentiles", err.Error())
+
+		return
+	}
+
+	layout, err := a.getLayout(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid layout", err.Error())
+
+		return
+	}
+
+	opts := []builder.DashboardBuilderOpt{
+		builder.WithSettings(dashboardSettings),
+		builder.WithFilter(filter),
+		builder.WithIngress(a.ingress),
+		builder.WithCollapsedSections(collapsedSections...),
+		builder.WithAnnotations(annotations),
+		builder.WithPercentiles(percentiles),
+		builder.WithTheme(a.theme),
+		builder.WithThresholds(a.thresholds),
+		builder.WithLayout(layout),
+	}
+
+	if state.Templating.Value {
+		opts = append(opts, builder.WithTemplating())
+	}
+
+	if httpAggregate != nil {
+		opts = append(opts, builder.WithHttpServerAggregate(httpAggregate))
+	}
+
+	extraSections, err := a.getExtraSections(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid extra sections", err.Error())
+
+		return
+	}
+
+	db, err := builder.NewDashboardBuilder(resourceNames, a.getOrchestratorName(state), opts...)
+	if err != nil {
+		response.Diagnostics.AddError("can not create dashboard", err.Error())
+
+		return
+	}
+
+	addExtraSections := func(position string) {
+		for _, section := range extraSections[position] {
+			if err := db.AddExtraSection(section, state.AppId()); err != nil {
+				response.Diagnostics.AddError("invalid extra sections", err.Error())
+			}
+		}
+	}
+
+	db.AddOverview(metadata)
+	addExtraSections(builder.ExtraSectionPositionTop)
 	db.AddServiceAndTask()
-	db.AddPanel(builder.NewPanelRow("Errors & Warnings"))
-	db.AddPanel(builder.NewPanelError)
-	db.AddPanel(builder.NewPanelWarn)
-	db.AddPanel(builder.NewPanelLogs)
+	addExtraSections(builder.ExtraSectionPositionAfterResourceUsage)
+	db.AddErrorsAndWarnings()
+	addExtraSections(builder.ExtraSectionPositionAfterErrors)
 
-	a.addHttpServers(metadata, resourceNames, db)
+	a.addHttpServers(metadata, db)
+	addExtraSections(builder.ExtraSectionPositionAfterHttpServers)
 
 	// Sort stream consumers by name for consistent ordering
 	streamConsumers := metadata.Stream.Consumers
@@ -173,6 +731,15 @@ func (a *ApplicationDashboardDefinitionDataSource) Read(ctx context.Context, req
 		db.AddCloudAwsSqsQueue(queue)
 	}
 
+	// Sort SNS topics by topic name for consistent ordering
+	snsTopics := metadata.Cloud.Aws.Sns.Topics
+	sort.Slice(snsTopics, func(i, j int) bool {
+		return snsTopics[i].TopicName < snsTopics[j].TopicName
+	})
+	for _, topic := range snsTopics {
+		db.AddCloudAwsSnsTopic(topic)
+	}
+
 	// Sort DynamoDB tables by table name for consistent ordering
 	dynamoTables := metadata.Cloud.Aws.Dynamodb.Tables
 	sort.Slice(dynamoTables, func(i, j int) bool {
@@ -182,117 +749,470 @@ func (a *ApplicationDashboardDefinitionDataSource) Read(ctx context.Context, req
 		db.AddDynamoDbTable(table)
 	}
 
+	addExtraSections(builder.ExtraSectionPositionBottom)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	dashboard := db.Build(state.Title.Value)
 
-	body, err := json.Marshal(dashboard)
+	body, err := builder.MarshalCanonicalJSON(dashboard)
 	if err != nil {
 		response.Diagnostics.AddError("can not create dashboard", err.Error())
+
+		return
 	}
 
 	state.Body = types.String{
 		Value: string(body),
 	}
+	state.BodySha256 = types.String{
+		Value: fmt.Sprintf("%x", sha256.Sum256(body)),
+	}
 
 	diags = response.State.Set(ctx, state)
 	response.Diagnostics.Append(diags...)
 }
 
-func (a *ApplicationDashboardDefinitionDataSource) getResourceNames(ctx context.Context, state *ApplicationDashboardDefinitionData, response *tfsdk.ReadDataSourceResponse) (*builder.ResourceNames, error) {
-	var err error
+func (a *ApplicationDashboardDefinitionDataSource) getOrchestratorName(state *ApplicationDashboardDefinitionData) string {
+	if state.Orchestrator.IsNull() || state.Orchestrator.Value == "" {
+		return a.orchestrator
+	}
 
-	// Always available
-	cloudwatchNamespace := builder.Augment(a.resourceNamePatterns.CloudwatchNamespace, state.AppId())
-	grafanaCloudWatchDatasourceName := builder.Augment(a.resourceNamePatterns.GrafanaCloudWatchDatasource, state.AppId())
-	grafanaElasticsearchDatasourceName := builder.Augment(a.resourceNamePatterns.GrafanaElasticsearchDatasource, state.AppId())
+	return state.Orchestrator.Value
+}
 
-	// only available when orchestrator is ecs => ECS/EC2-LB+TG related
-	var targetGroups []builder.ElbTargetGroup
-	var ecsTaskDefinitionName string
-	var ecsClusterName string
-	var ecsServiceName string
+func (a *ApplicationDashboardDefinitionDataSource) getCollapsedSections(ctx context.Context, state *ApplicationDashboardDefinitionData) ([]string, error) {
+	if state.Collapse.IsNull() {
+		return nil, nil
+	}
 
-	// only available when orchestrator is kubernetes => Kubernetes/Traefik related
-	var kubernetesNamespace string
-	var kubernetesPod string
-	var kubernetesDeployment string
-	var traefikServiceName string
+	var data collapseData
+	if diags := state.Collapse.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert collapse attribute to native type: %v", diags)
+	}
 
-	containers := make([]string, 0)
-	diags := state.Containers.ElementsAs(ctx, &containers, false)
-	response.Diagnostics.Append(diags...)
+	sections := make([]string, 0)
+	for section, collapse := range map[string]types.Bool{
+		builder.DashboardSectionAll:        data.All,
+		builder.DashboardSectionHttpRoutes: data.HttpRoutes,
+		builder.DashboardSectionKinesis:    data.Kinesis,
+		builder.DashboardSectionSqs:        data.Sqs,
+		builder.DashboardSectionSns:        data.Sns,
+		builder.DashboardSectionDdb:        data.Ddb,
+	} {
+		if collapse.Value {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections, nil
+}
+
+func (a *ApplicationDashboardDefinitionDataSource) getAnnotationSettings(ctx context.Context, state *ApplicationDashboardDefinitionData) (builder.DashboardAnnotationSettings, error) {
+	settings := builder.DashboardAnnotationSettings{
+		Deployments: true,
+	}
+
+	if state.Annotations.IsNull() {
+		return settings, nil
+	}
+
+	var data annotationsData
+	if diags := state.Annotations.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+		return settings, fmt.Errorf("failed to convert annotations attribute to native type: %v", diags)
+	}
+
+	if !data.Deployments.IsNull() {
+		settings.Deployments = data.Deployments.Value
+	}
 
-	switch orchestrator := a.orchestrator; orchestrator {
-	case orchestratorEcs:
-		ecsClusterName = builder.Augment(a.resourceNamePatterns.EcsCluster, state.AppId())
-		ecsServiceName = builder.Augment(a.resourceNamePatterns.EcsService, state.AppId())
-		targetGroups, ecsTaskDefinitionName, err = a.getEc2AndEcsData(ctx, response, ecsClusterName, ecsServiceName)
+	settings.IconColor = data.IconColor.Value
+
+	return settings, nil
+}
+
+func (a *ApplicationDashboardDefinitionDataSource) getDashboardSettings(ctx context.Context, state *ApplicationDashboardDefinitionData) (builder.DashboardSettings, error) {
+	settings := builder.NewDashboardSettings(state.AppId())
+
+	if !state.Uid.IsNull() && state.Uid.Value != "" {
+		settings.Uid = state.Uid.Value
+	}
+
+	if !state.Tags.IsNull() {
+		settings.Tags = make([]string, 0)
+		if diags := state.Tags.ElementsAs(ctx, &settings.Tags, false); diags.HasError() {
+			return settings, fmt.Errorf("failed to convert tags attribute to native type: %v", diags)
+		}
+	}
+
+	if !state.Refresh.IsNull() {
+		settings.Refresh = state.Refresh.Value
+	}
+
+	if !state.Time.IsNull() {
+		var data timeData
+		if diags := state.Time.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+			return settings, fmt.Errorf("failed to convert time attribute to native type: %v", diags)
+		}
+
+		if !data.From.IsNull() {
+			settings.Time.From = data.From.Value
+		}
+
+		if !data.To.IsNull() {
+			settings.Time.To = data.To.Value
+		}
+	}
+
+	if !state.Timezone.IsNull() {
+		settings.Timezone = state.Timezone.Value
+	}
+
+	if !state.GraphTooltip.IsNull() {
+		graphTooltip, ok := graphTooltips[state.GraphTooltip.Value]
+		if !ok {
+			return settings, fmt.Errorf("'%s' is not a valid graph tooltip, choose between %v", state.GraphTooltip.Value, availableGraphTooltips())
+		}
+
+		settings.GraphTooltip = graphTooltip
+	}
+
+	links := make([]linkData, 0)
+	if diags := state.Links.ElementsAs(ctx, &links, false); diags.HasError() {
+		return settings, fmt.Errorf("failed to convert links attribute to native type: %v", diags)
+	}
+
+	for _, data := range links {
+		link := builder.DashboardLink{
+			AsDropdown:  data.AsDropdown.Value,
+			Icon:        data.Icon.Value,
+			IncludeVars: data.IncludeVars.Value,
+			KeepTime:    data.KeepTime.Value,
+			Tags:        make([]string, 0),
+			TargetBlank: data.TargetBlank.Value,
+			Title:       data.Title.Value,
+			Tooltip:     data.Tooltip.Value,
+			Type:        data.Type.Value,
+			Url:         data.Url.Value,
+		}
+
+		if diags := data.Tags.ElementsAs(ctx, &link.Tags, false); diags.HasError() {
+			return settings, fmt.Errorf("failed to convert links.tags attribute to native type: %v", diags)
+		}
+
+		if link.Type == "" {
+			link.Type = "link"
+		}
+
+		if link.Icon == "" {
+			link.Icon = "external link"
+		}
+
+		settings.Links = append(settings.Links, link)
+	}
+
+	return settings, nil
+}
+
+func (a *ApplicationDashboardDefinitionDataSource) getFilter(ctx context.Context, state *ApplicationDashboardDefinitionData) (*builder.DashboardFilter, error) {
+	settings := builder.DashboardFilterSettings{
+		RoutePaths: builder.NameFilterSettings{
+			Exclude: defaultRoutePathExcludes,
+		},
+	}
+
+	if state.Filter.IsNull() {
+		return builder.NewDashboardFilter(settings)
+	}
+
+	var data filterData
+	if diags := state.Filter.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert filter attribute to native type: %v", diags)
+	}
+
+	if diags := data.IncludeSections.ElementsAs(ctx, &settings.IncludeSections, false); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert filter.include_sections attribute to native type: %v", diags)
+	}
+
+	if diags := data.ExcludeSections.ElementsAs(ctx, &settings.ExcludeSections, false); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert filter.exclude_sections attribute to native type: %v", diags)
+	}
+
+	for name, target := range map[string]struct {
+		object   types.Object
+		settings *builder.NameFilterSettings
+	}{
+		"route_paths":   {data.RoutePaths, &settings.RoutePaths},
+		"route_methods": {data.RouteMethods, &settings.RouteMethods},
+		"queue_names":   {data.QueueNames, &settings.QueueNames},
+		"table_names":   {data.TableNames, &settings.TableNames},
+		"stream_names":  {data.StreamNames, &settings.StreamNames},
+		"topic_names":   {data.TopicNames, &settings.TopicNames},
+	} {
+		if target.object.IsNull() {
+			continue
+		}
+
+		if err := getNameFilterSettings(ctx, "filter."+name, target.object, target.settings); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.NewDashboardFilter(settings)
+}
+
+// getNameFilterSettings reads the patterns of a name filter, the configured lists replace the ones of the settings
+func getNameFilterSettings(ctx context.Context, path string, object types.Object, settings *builder.NameFilterSettings) error {
+	if object.IsNull() {
+		return nil
+	}
+
+	var data nameFilterData
+	if diags := object.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+		return fmt.Errorf("failed to convert %s attribute to native type: %v", path, diags)
+	}
+
+	if !data.Include.IsNull() {
+		settings.Include = nil
+		if diags := data.Include.ElementsAs(ctx, &settings.Include, false); diags.HasError() {
+			return fmt.Errorf("failed to convert %s.include attribute to native type: %v", path, diags)
+		}
+	}
+
+	if !data.Exclude.IsNull() {
+		settings.Exclude = nil
+		if diags := data.Exclude.ElementsAs(ctx, &settings.Exclude, false); diags.HasError() {
+			return fmt.Errorf("failed to convert %s.exclude attribute to native type: %v", path, diags)
+		}
+	}
+
+	return nil
+}
+
+func (a *ApplicationDashboardDefinitionDataSource) getPercentiles(ctx context.Context, state *ApplicationDashboardDefinitionData) ([]builder.Percentile, error) {
+	values := make([]float64, 0)
+	if diags := state.Percentiles.ElementsAs(ctx, &values, false); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert percentiles attribute to native type: %v", diags)
+	}
+
+	return builder.NewPercentiles(values...)
+}
+
+// getHttpAggregate returns nil if the http servers are rendered with a section per route
+func (a *ApplicationDashboardDefinitionDataSource) getHttpAggregate(ctx context.Context, state *ApplicationDashboardDefinitionData) (*builder.HttpServerAggregate, error) {
+	if state.HttpAggregate.IsNull() {
+		return nil, nil
+	}
+
+	var data httpAggregateData
+	if diags := state.HttpAggregate.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert http_aggregate attribute to native type: %v", diags)
+	}
+
+	settings := builder.HttpServerAggregateSettings{
+		TopRoutes:     int(data.TopRoutes.Value),
+		RouteSections: data.RouteSections.Value,
+	}
+
+	if err := getNameFilterSettings(ctx, "http_aggregate.route_paths", data.RoutePaths, &settings.RoutePaths); err != nil {
+		return nil, err
+	}
+
+	return builder.NewHttpServerAggregate(settings)
+}
+
+// getLayout returns nil if the panels keep their default sizes
+func (a *ApplicationDashboardDefinitionDataSource) getLayout(ctx context.Context, state *ApplicationDashboardDefinitionData) (*builder.PanelLayout, error) {
+	if state.Layout.IsNull() {
+		return nil, nil
+	}
+
+	var data layoutData
+	if diags := state.Layout.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert layout attribute to native type: %v", diags)
+	}
+
+	sectionsData := make(map[string]panelSizeData)
+	if diags := data.Sections.ElementsAs(ctx, &sectionsData, false); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert layout.sections attribute to native type: %v", diags)
+	}
+
+	settings := builder.DashboardLayoutSettings{
+		Panels: builder.PanelSizeSettings{
+			Width:        int(data.PanelWidth.Value),
+			Height:       int(data.PanelHeight.Value),
+			PanelsPerRow: int(data.PanelsPerRow.Value),
+		},
+		Sections: make(map[string]builder.PanelSizeSettings, len(sectionsData)),
+	}
+
+	for section, size := range sectionsData {
+		settings.Sections[section] = builder.PanelSizeSettings{
+			Width:        int(size.PanelWidth.Value),
+			Height:       int(size.PanelHeight.Value),
+			PanelsPerRow: int(size.PanelsPerRow.Value),
+		}
+	}
+
+	return builder.NewPanelLayout(settings)
+}
+
+// getExtraSections returns the extra sections grouped by their position, in the order they are configured
+func (a *ApplicationDashboardDefinitionDataSource) getExtraSections(ctx context.Context, state *ApplicationDashboardDefinitionData) (map[string][]builder.ExtraSection, error) {
+	sectionsData := make([]extraSectionData, 0)
+	if diags := state.ExtraSections.ElementsAs(ctx, &sectionsData, false); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert extra_sections attribute to native type: %v", diags)
+	}
+
+	sections := make(map[string][]builder.ExtraSection)
+
+	for _, sectionData := range sectionsData {
+		position, err := builder.ParseExtraSectionPosition(sectionData.Position.Value)
 		if err != nil {
 			return nil, err
 		}
-	case orchestratorKubernetes:
-		kubernetesNamespace = builder.Augment(a.resourceNamePatterns.KubernetesNamespace, state.AppId())
-		kubernetesPod = builder.Augment(a.resourceNamePatterns.KubernetesPod, state.AppId())
-		kubernetesDeployment = kubernetesPod // KubernetesPod pattern is actually the deployment name
-		traefikServiceName = builder.Augment(a.resourceNamePatterns.TraefikServiceName, state.AppId())
+
+		panelsData := make([]extraPanelData, 0)
+		if diags := sectionData.Panels.ElementsAs(ctx, &panelsData, false); diags.HasError() {
+			return nil, fmt.Errorf("failed to convert extra_sections.panels attribute to native type: %v", diags)
+		}
+
+		section := builder.ExtraSection{
+			Title:     sectionData.Title.Value,
+			Position:  position,
+			Collapsed: sectionData.Collapsed.Value,
+			Panels:    make([]builder.ExtraPanel, len(panelsData)),
+		}
+
+		for i, panelData := range panelsData {
+			panel := builder.ExtraPanel{
+				RawJson:    panelData.RawJson.Value,
+				Datasource: panelData.Datasource.Value,
+				Queries:    make([]string, 0),
+				Title:      panelData.Title.Value,
+				Type:       panelData.Type.Value,
+				Unit:       panelData.Unit.Value,
+			}
+
+			if diags := panelData.Queries.ElementsAs(ctx, &panel.Queries, false); diags.HasError() {
+				return nil, fmt.Errorf("failed to convert extra_sections.panels.queries attribute to native type: %v", diags)
+			}
+
+			section.Panels[i] = panel
+		}
+
+		sections[position] = append(sections[position], section)
 	}
 
+	return sections, nil
+}
+
+func (a *ApplicationDashboardDefinitionDataSource) getResourceNames(ctx context.Context, state *ApplicationDashboardDefinitionData, response *tfsdk.ReadDataSourceResponse) (*builder.ResourceNames, error) {
+	containers := make([]string, 0)
+	diags := state.Containers.ElementsAs(ctx, &containers, false)
+	response.Diagnostics.Append(diags...)
+
+	excludeContainers := make([]string, 0)
+	diags = state.ExcludeContainers.ElementsAs(ctx, &excludeContainers, false)
+	response.Diagnostics.Append(diags...)
+
 	resourceNames := &builder.ResourceNames{
-		CloudwatchNamespace:                cloudwatchNamespace,
-		EcsCluster:                         ecsClusterName,
-		EcsService:                         ecsServiceName,
-		EcsTaskDefinition:                  ecsTaskDefinitionName,
+		CloudwatchNamespace:                builder.Augment(a.resourceNamePatterns.CloudwatchNamespace, state.AppId()),
 		Environment:                        state.Environment.Value,
-		GrafanaCloudWatchDatasourceName:    grafanaCloudWatchDatasourceName,
-		GrafanaElasticsearchDatasourceName: grafanaElasticsearchDatasourceName,
-		KubernetesDeployment:               kubernetesDeployment,
-		KubernetesNamespace:                kubernetesNamespace,
-		KubernetesPod:                      kubernetesPod,
-		TraefikServiceName:                 traefikServiceName,
-		TargetGroups:                       targetGroups,
+		GrafanaCloudWatchDatasourceName:    builder.Augment(a.resourceNamePatterns.GrafanaCloudWatchDatasource, state.AppId()),
+		GrafanaElasticsearchDatasourceName: builder.Augment(a.resourceNamePatterns.GrafanaElasticsearchDatasource, state.AppId()),
 		Containers:                         containers,
 	}
 
-	return resourceNames, nil
-}
+	orchestratorName := a.getOrchestratorName(state)
+	orchestrator, ok := builder.GetOrchestrator(orchestratorName)
+	if !ok {
+		err := fmt.Errorf("'%s' is not a valid orchestrator, choose between %v", orchestratorName, builder.AvailableOrchestrators())
+		response.Diagnostics.AddError("invalid orchestrator", err.Error())
 
-func (a *ApplicationDashboardDefinitionDataSource) getEc2AndEcsData(ctx context.Context, response *tfsdk.ReadDataSourceResponse, ecsClusterName string, ecsServiceName string) ([]builder.ElbTargetGroup, string, error) {
-	var targetGroups []builder.ElbTargetGroup
-	var ecsTaskDefinitionName *string
+		return nil, err
+	}
 
-	ecsClient, err := builder.NewEcsClient(ctx, ecsClusterName, ecsServiceName)
-	if err != nil {
-		response.Diagnostics.AddError("can not get ecs client", err.Error())
+	var workloadKind string
+	if !state.WorkloadKind.IsNull() && state.WorkloadKind.Value != "" {
+		var err error
+		if workloadKind, err = builder.ParseKubernetesWorkloadKind(state.WorkloadKind.Value); err != nil {
+			response.Diagnostics.AddError("invalid workload kind", err.Error())
 
-		return nil, "", err
+			return nil, err
+		}
 	}
 
-	targetGroups, err = ecsClient.GetElbTargetGroups(ctx)
-	if err != nil {
-		response.Diagnostics.AddError("can not get target groups", err.Error())
+	settings := builder.OrchestratorSettings{
+		AwsClients:                   a.awsClients,
+		KubernetesClient:             a.kubernetesClient,
+		KubernetesWorkloadKind:       workloadKind,
+		KubernetesSelectPodsByLabels: a.selectPodsByLabels,
+		NamePatterns: builder.OrchestratorNamePatterns{
+			EcsCluster:          a.resourceNamePatterns.EcsCluster,
+			EcsService:          a.resourceNamePatterns.EcsService,
+			KubernetesNamespace: a.resourceNamePatterns.KubernetesNamespace,
+			KubernetesPod:       a.resourceNamePatterns.KubernetesPod,
+			TraefikServiceName:  a.resourceNamePatterns.TraefikServiceName,
+		},
+		ExcludeContainers: excludeContainers,
+	}
 
-		return nil, "", err
+	if err := orchestrator.DiscoverResources(ctx, settings, state.AppId(), resourceNames); err != nil {
+		response.Diagnostics.AddError("can not discover orchestrator resources", err.Error())
+
+		return nil, err
 	}
 
-	ecsTaskDefinitionName, err = ecsClient.GetTaskDefinitionName(ctx)
-	if err != nil {
-		response.Diagnostics.AddError("can not get ecs task definition name", err.Error())
+	if err := a.discoverIngressResources(ctx, orchestratorName, state, resourceNames); err != nil {
+		response.Diagnostics.AddError("can not discover ingress resources", err.Error())
 
-		return nil, "", err
+		return nil, err
 	}
 
-	return targetGroups, *ecsTaskDefinitionName, err
+	if orchestrator.UsesContainers() && len(resourceNames.Containers) == 0 {
+		err := fmt.Errorf("there are no containers for %s-%s-%s-%s-%s: set the containers attribute or check the exclude_containers patterns", state.Project.Value, state.Environment.Value, state.Family.Value, state.Group.Value, state.Application.Value)
+		response.Diagnostics.AddError("can not determine containers", err.Error())
+
+		return nil, err
+	}
+
+	return resourceNames, nil
 }
 
-func (a *ApplicationDashboardDefinitionDataSource) addHttpServers(metadata *builder.MetadataApplication, resourceNames *builder.ResourceNames, db *builder.DashboardBuilder) {
-	if len(metadata.HttpServers) == 0 {
-		return
+func (a *ApplicationDashboardDefinitionDataSource) discoverIngressResources(ctx context.Context, orchestratorName string, state *ApplicationDashboardDefinitionData, resourceNames *builder.ResourceNames) error {
+	if orchestratorName != orchestratorKubernetes {
+		return nil
+	}
+
+	ingress, ok := builder.GetIngress(a.ingress)
+	if !ok {
+		return fmt.Errorf("'%s' is not a valid ingress, choose between %v", a.ingress, builder.AvailableIngresses())
+	}
+
+	settings := builder.IngressSettings{
+		AwsClients: a.awsClients,
+		NamePatterns: builder.IngressNamePatterns{
+			NginxIngress:      a.resourceNamePatterns.NginxIngress,
+			IstioService:      a.resourceNamePatterns.IstioService,
+			AwsLbTargetGroup:  a.resourceNamePatterns.AwsLbTargetGroup,
+			AwsLbIngressStack: a.resourceNamePatterns.AwsLbIngressStack,
+			AwsLbCluster:      a.resourceNamePatterns.AwsLbCluster,
+		},
 	}
 
-	for i := range resourceNames.TargetGroups {
-		db.AddElbTargetGroup(i)
+	return ingress.DiscoverResources(ctx, settings, state.AppId(), resourceNames)
+}
+
+func (a *ApplicationDashboardDefinitionDataSource) addHttpServers(metadata *builder.MetadataApplication, db *builder.DashboardBuilder) {
+	if len(metadata.HttpServers) == 0 {
+		return
 	}
 
-	db.AddTraefikService()
+	db.AddElbTargetGroups()
+	db.AddIngress()
 
 	// Sort HTTP servers by name for consistent ordering
 	httpServers := metadata.HttpServers
@@ -311,12 +1231,6 @@ func (a *ApplicationDashboardDefinitionDataSource) addHttpServers(metadata *buil
 			return handlers[i].Method < handlers[j].Method
 		})
 
-		for _, route := range handlers {
-			if route.Path == "/health" {
-				continue
-			}
-
-			db.AddHttpServerHandler(server.Name, route)
-		}
+		db.AddHttpServer(server)
 	}
 }
@@ -0,0 +1,315 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+)
+
+type ApplicationPrometheusRulesData struct {
+	Project           types.String `tfsdk:"project"`
+	Environment       types.String `tfsdk:"environment"`
+	Family            types.String `tfsdk:"family"`
+	Group             types.String `tfsdk:"group"`
+	Application       types.String `tfsdk:"application"`
+	WorkloadKind      types.String `tfsdk:"workload_kind"`
+	Thresholds        types.Object `tfsdk:"thresholds"`
+	Labels            types.Map    `tfsdk:"labels"`
+	ManifestNamespace types.String `tfsdk:"manifest_namespace"`
+	ManifestLabels    types.Map    `tfsdk:"manifest_labels"`
+	RulesYaml         types.String `tfsdk:"rules_yaml"`
+	ManifestYaml      types.String `tfsdk:"manifest_yaml"`
+}
+
+func (d ApplicationPrometheusRulesData) AppId() builder.AppId {
+	return builder.AppId{
+		Project:     d.Project.Value,
+		Environment: d.Environment.Value,
+		Family:      d.Family.Value,
+		Group:       d.Group.Value,
+		Application: d.Application.Value,
+	}
+}
+
+type prometheusThresholdsData struct {
+	For              types.String  `tfsdk:"for"`
+	PodRestarts      types.Int64   `tfsdk:"pod_restarts"`
+	CpuSaturation    types.Float64 `tfsdk:"cpu_saturation"`
+	MemorySaturation types.Float64 `tfsdk:"memory_saturation"`
+	MissingReplicas  types.Float64 `tfsdk:"missing_replicas"`
+	Http5xxRatio     types.Float64 `tfsdk:"http_5xx_ratio"`
+	LatencyP99       types.Float64 `tfsdk:"latency_p99"`
+}
+
+type ApplicationPrometheusRulesDatasourceType struct{}
+
+func (a *ApplicationPrometheusRulesDatasourceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	defaults := builder.DefaultPrometheusRulesSettings()
+
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"project": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"environment": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"family": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"group": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"application": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"workload_kind": {
+				Type:                types.StringType,
+				Optional:            true,
+				MarkdownDescription: fmt.Sprintf("Kind of the kubernetes workload, choose between %v. If omitted, it is looked up from the kubernetes api if the provider has a kubernetes configuration, otherwise a deployment is assumed", builder.KubernetesWorkloadKinds()),
+			},
+			"thresholds": {
+				Optional:            true,
+				MarkdownDescription: `Changes the thresholds of the rules, a threshold of 0 disables the rule`,
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"for": {
+						Type:                types.StringType,
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("How long a condition has to hold before the alert fires (default: %s)", defaults.For),
+					},
+					"pod_restarts": {
+						Type:                types.Int64Type,
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("Container restarts within 15 minutes (default: %d)", defaults.PodRestarts),
+					},
+					"cpu_saturation": {
+						Type:                types.Float64Type,
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("Cpu usage in percent of the requests (default: %v)", defaults.CpuSaturation),
+					},
+					"memory_saturation": {
+						Type:                types.Float64Type,
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("Memory usage in percent of the requests (default: %v)", defaults.MemorySaturation),
+					},
+					"missing_replicas": {
+						Type:                types.Float64Type,
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("Share of the desired replicas in percent which aren't ready (default: %v)", defaults.MissingReplicas),
+					},
+					"http_5xx_ratio": {
+						Type:                types.Float64Type,
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("Share of the traefik requests in percent which failed with a 5xx status (default: %v)", defaults.Http5xxRatio),
+					},
+					"latency_p99": {
+						Type:                types.Float64Type,
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("p99 latency of the traefik requests in seconds (default: %v)", defaults.LatencyP99),
+					},
+				}),
+			},
+			"labels": {
+				Type:                types.MapType{ElemType: types.StringType},
+				Optional:            true,
+				MarkdownDescription: `Labels added to every rule, e.g. the severity or the team to route the alerts to`,
+			},
+			"manifest_namespace": {
+				Type:                types.StringType,
+				Optional:            true,
+				MarkdownDescription: `Namespace of the PrometheusRule manifest (default: the kubernetes namespace of the application)`,
+			},
+			"manifest_labels": {
+				Type:                types.MapType{ElemType: types.StringType},
+				Optional:            true,
+				MarkdownDescription: `Labels of the PrometheusRule manifest, e.g. the ones selected by the ruleSelector of the prometheus operator`,
+			},
+			"rules_yaml": {
+				Type:                types.StringType,
+				Computed:            true,
+				MarkdownDescription: `The rule group as a prometheus rule file`,
+			},
+			"manifest_yaml": {
+				Type:                types.StringType,
+				Computed:            true,
+				MarkdownDescription: `The rule group as a monitoring.coreos.com/v1 PrometheusRule manifest`,
+			},
+		},
+	}, nil
+}
+
+func (a *ApplicationPrometheusRulesDatasourceType) NewDataSource(_ context.Context, provider tfsdk.Provider) (tfsdk.DataSource, diag.Diagnostics) {
+	return &ApplicationPrometheusRulesDatasource{
+		kubernetesClient:     provider.(*GosolineProvider).kubernetesClient,
+		selectPodsByLabels:   provider.(*GosolineProvider).kubernetesSelectPodsByLabels,
+		resourceNamePatterns: provider.(*GosolineProvider).resourceNamePatterns,
+		ingress:              provider.(*GosolineProvider).ingress,
+	}, nil
+}
+
+type ApplicationPrometheusRulesDatasource struct {
+	kubernetesClient     *builder.KubernetesClient
+	selectPodsByLabels   bool
+	resourceNamePatterns ResourceNamePatterns
+	ingress              string
+}
+
+func (a *ApplicationPrometheusRulesDatasource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest, response *tfsdk.ReadDataSourceResponse) {
+	state := &ApplicationPrometheusRulesData{}
+
+	diags := request.Config.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	resourceNames, err := a.getResourceNames(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("can not discover kubernetes resources", err.Error())
+
+		return
+	}
+
+	settings, err := a.getRulesSettings(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError("invalid thresholds", err.Error())
+
+		return
+	}
+
+	group, err := builder.NewPrometheusRuleGroup(resourceNames, settings)
+	if err != nil {
+		response.Diagnostics.AddError("invalid thresholds", err.Error())
+
+		return
+	}
+
+	manifestLabels := make(map[string]string)
+	if diags := state.ManifestLabels.ElementsAs(ctx, &manifestLabels, false); diags.HasError() {
+		response.Diagnostics.Append(diags...)
+
+		return
+	}
+
+	manifestNamespace := resourceNames.KubernetesNamespace
+	if !state.ManifestNamespace.IsNull() && state.ManifestNamespace.Value != "" {
+		manifestNamespace = state.ManifestNamespace.Value
+	}
+
+	rules, err := group.RuleFile()
+	if err != nil {
+		response.Diagnostics.AddError("can not create rule file", err.Error())
+
+		return
+	}
+
+	manifest, err := group.Manifest(manifestNamespace, manifestLabels)
+	if err != nil {
+		response.Diagnostics.AddError("can not create PrometheusRule manifest", err.Error())
+
+		return
+	}
+
+	state.RulesYaml = types.String{Value: string(rules)}
+	state.ManifestYaml = types.String{Value: string(manifest)}
+
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// getResourceNames resolves the names of the kubernetes workload the same way the dashboard does
+func (a *ApplicationPrometheusRulesDatasource) getResourceNames(ctx context.Context, state *ApplicationPrometheusRulesData) (*builder.ResourceNames, error) {
+	var err error
+	var workloadKind string
+
+	if !state.WorkloadKind.IsNull() && state.WorkloadKind.Value != "" {
+		if workloadKind, err = builder.ParseKubernetesWorkloadKind(state.WorkloadKind.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	orchestrator, ok := builder.GetOrchestrator(orchestratorKubernetes)
+	if !ok {
+		return nil, fmt.Errorf("the %s orchestrator is not registered", orchestratorKubernetes)
+	}
+
+	resourceNames := &builder.ResourceNames{
+		Environment: state.Environment.Value,
+	}
+
+	settings := builder.OrchestratorSettings{
+		KubernetesClient:             a.kubernetesClient,
+		KubernetesWorkloadKind:       workloadKind,
+		KubernetesSelectPodsByLabels: a.selectPodsByLabels,
+		NamePatterns: builder.OrchestratorNamePatterns{
+			KubernetesNamespace: a.resourceNamePatterns.KubernetesNamespace,
+			KubernetesPod:       a.resourceNamePatterns.KubernetesPod,
+			TraefikServiceName:  a.resourceNamePatterns.TraefikServiceName,
+		},
+	}
+
+	if err = orchestrator.DiscoverResources(ctx, settings, state.AppId(), resourceNames); err != nil {
+		return nil, err
+	}
+
+	return resourceNames, nil
+}
+
+// getRulesSettings returns the default settings, overridden by the thresholds and labels which are set
+func (a *ApplicationPrometheusRulesDatasource) getRulesSettings(ctx context.Context, state *ApplicationPrometheusRulesData) (builder.PrometheusRulesSettings, error) {
+	settings := builder.DefaultPrometheusRulesSettings()
+	settings.IncludeTraefik = a.ingress == ingressTraefik
+
+	if diags := state.Labels.ElementsAs(ctx, &settings.Labels, false); diags.HasError() {
+		return settings, fmt.Errorf("failed to convert labels attribute to native type: %v", diags)
+	}
+
+	if state.Thresholds.IsNull() {
+		return settings, nil
+	}
+
+	var data prometheusThresholdsData
+	if diags := state.Thresholds.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+		return settings, fmt.Errorf("failed to convert thresholds attribute to native type: %v", diags)
+	}
+
+	if !data.For.IsNull() {
+		settings.For = data.For.Value
+	}
+
+	if !data.PodRestarts.IsNull() {
+		settings.PodRestarts = int(data.PodRestarts.Value)
+	}
+
+	if !data.CpuSaturation.IsNull() {
+		settings.CpuSaturation = data.CpuSaturation.Value
+	}
+
+	if !data.MemorySaturation.IsNull() {
+		settings.MemorySaturation = data.MemorySaturation.Value
+	}
+
+	if !data.MissingReplicas.IsNull() {
+		settings.MissingReplicas = data.MissingReplicas.Value
+	}
+
+	if !data.Http5xxRatio.IsNull() {
+		settings.Http5xxRatio = data.Http5xxRatio.Value
+	}
+
+	if !data.LatencyP99.IsNull() {
+		settings.LatencyP99 = data.LatencyP99.Value
+	}
+
+	return settings, nil
+}
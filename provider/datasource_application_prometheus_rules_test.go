@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/justtrackio/terraform-provider-gosoline/builder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type prometheusRuleManifest struct {
+	Metadata struct {
+		Name      string            `yaml:"name"`
+		Namespace string            `yaml:"namespace"`
+		Labels    map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Groups []builder.PrometheusRuleGroup `yaml:"groups"`
+	} `yaml:"spec"`
+}
+
+func newStringMap(values map[string]string) tftypes.Value {
+	elements := make(map[string]tftypes.Value, len(values))
+	for key, value := range values {
+		elements[key] = tftypes.NewValue(tftypes.String, value)
+	}
+
+	return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, elements)
+}
+
+func providePrometheusRulesConfig(t *testing.T, values map[string]tftypes.Value, thresholds map[string]tftypes.Value) tfsdk.Config {
+	for name, value := range provideAppIdValues() {
+		values[name] = value
+	}
+
+	if thresholds != nil {
+		config := provideConfig(t, &ApplicationPrometheusRulesDatasourceType{}, values)
+		values["thresholds"] = newObjectValue(attributeType(config, "thresholds").(tftypes.Object), thresholds)
+	}
+
+	return provideConfig(t, &ApplicationPrometheusRulesDatasourceType{}, values)
+}
+
+func providePrometheusRulesDatasource() *ApplicationPrometheusRulesDatasource {
+	return &ApplicationPrometheusRulesDatasource{
+		resourceNamePatterns: ResourceNamePatterns{
+			KubernetesNamespace: defaultKubernetesNamespaceNamePattern,
+			KubernetesPod:       defaultKubernetesPodNamePattern,
+			TraefikServiceName:  defaultTraefikServiceNameNamePattern,
+		},
+		ingress: ingressTraefik,
+	}
+}
+
+func readPrometheusRuleManifest(t *testing.T, state tfsdk.State) prometheusRuleManifest {
+	data := ApplicationPrometheusRulesData{}
+	require.False(t, state.Get(context.Background(), &data).HasError())
+
+	manifest := prometheusRuleManifest{}
+	require.NoError(t, yaml.Unmarshal([]byte(data.ManifestYaml.Value), &manifest))
+	require.Len(t, manifest.Spec.Groups, 1)
+
+	return manifest
+}
+
+func TestApplicationPrometheusRulesSchema(t *testing.T) {
+	schema, diags := (&ApplicationPrometheusRulesDatasourceType{}).GetSchema(context.Background())
+	assert.False(t, diags.HasError())
+
+	assert.True(t, schema.Attributes["rules_yaml"].Computed)
+	assert.True(t, schema.Attributes["manifest_yaml"].Computed)
+	assert.Contains(t, schema.Attributes["thresholds"].Attributes.GetAttributes(), "missing_replicas")
+}
+
+func TestApplicationPrometheusRulesRead(t *testing.T) {
+	config := providePrometheusRulesConfig(t, map[string]tftypes.Value{
+		"manifest_labels": newStringMap(map[string]string{"release": "prometheus"}),
+	}, nil)
+
+	manifest := readPrometheusRuleManifest(t, readDataSource(t, providePrometheusRulesDatasource(), config))
+
+	// the manifest is put into the namespace of the application unless configured otherwise
+	assert.Equal(t, "prj-grp-app", manifest.Metadata.Name)
+	assert.Equal(t, "prj", manifest.Metadata.Namespace)
+	assert.Equal(t, map[string]string{"release": "prometheus"}, manifest.Metadata.Labels)
+	assert.Len(t, manifest.Spec.Groups[0].Rules, 6)
+
+	config = providePrometheusRulesConfig(t, map[string]tftypes.Value{
+		"manifest_namespace": tftypes.NewValue(tftypes.String, "monitoring"),
+	}, nil)
+
+	manifest = readPrometheusRuleManifest(t, readDataSource(t, providePrometheusRulesDatasource(), config))
+	assert.Equal(t, "monitoring", manifest.Metadata.Namespace)
+}
+
+func TestApplicationPrometheusRulesReadThresholds(t *testing.T) {
+	config := providePrometheusRulesConfig(t, map[string]tftypes.Value{
+		"labels": newStringMap(map[string]string{"severity": "warning"}),
+	}, map[string]tftypes.Value{
+		"for":              tftypes.NewValue(tftypes.String, "10m"),
+		"pod_restarts":     tftypes.NewValue(tftypes.Number, 5),
+		"missing_replicas": tftypes.NewValue(tftypes.Number, 0),
+		"latency_p99":      tftypes.NewValue(tftypes.Number, 0.5),
+	})
+
+	manifest := readPrometheusRuleManifest(t, readDataSource(t, providePrometheusRulesDatasource(), config))
+
+	alerts := make([]string, 0)
+	for _, rule := range manifest.Spec.Groups[0].Rules {
+		alerts = append(alerts, rule.Alert)
+
+		assert.Equal(t, "10m", rule.For)
+		assert.Equal(t, map[string]string{"severity": "warning"}, rule.Labels)
+	}
+
+	// a threshold of 0 disables the rule
+	assert.Equal(t, []string{"PodRestarts", "CpuSaturation", "MemorySaturation", "Http5xxRatio", "HttpLatencyP99"}, alerts)
+	assert.Contains(t, manifest.Spec.Groups[0].Rules[0].Expr, "> 5")
+	assert.Contains(t, manifest.Spec.Groups[0].Rules[4].Expr, "> 0.5")
+}
+
+func TestApplicationPrometheusRulesGetRulesSettings(t *testing.T) {
+	config := providePrometheusRulesConfig(t, map[string]tftypes.Value{}, map[string]tftypes.Value{
+		"cpu_saturation": tftypes.NewValue(tftypes.Number, 75),
+	})
+
+	state := &ApplicationPrometheusRulesData{}
+	require.False(t, config.Get(context.Background(), state).HasError())
+
+	settings, err := (&ApplicationPrometheusRulesDatasource{ingress: "nginx"}).getRulesSettings(context.Background(), state)
+	assert.NoError(t, err)
+
+	// the values which aren't set keep their default and the traefik rules are skipped for the other ingresses
+	expected := builder.DefaultPrometheusRulesSettings()
+	expected.CpuSaturation = 75
+	expected.IncludeTraefik = false
+
+	assert.Equal(t, expected, settings)
+}
@@ -13,15 +13,15 @@ import (
 	"github.com/thoas/go-funk"
 )
 
-var availableOrchestrators = []string{orchestratorEcs, orchestratorKubernetes}
-
 const (
 	orchestratorEcs                                  = "ecs"
 	orchestratorKubernetes                           = "kubernetes"
+	ingressTraefik                                   = "traefik"
 	defaultMetadataHostnameNamePattern               = "{scheme}://{group}-{app}.{family}.{env}.{metadata_domain}:{port}"
 	defaultMetadataUseHttps                          = true
 	defaultMetadataPort                              = 8070
 	defaultOrchestrator                              = orchestratorEcs
+	defaultIngress                                   = ingressTraefik
 	defaultEcsClusterNamePattern                     = "{env}"
 	defaultEcsServiceNamePattern                     = "{group}-{app}"
 	defaultCloudwatchNamespaceNamePattern            = "{project}/{env}/{family}/{group}-{app}"
@@ -30,6 +30,11 @@ const (
 	defaultKubernetesNamespaceNamePattern            = "{project}"
 	defaultKubernetesPodNamePattern                  = "{group}-{app}"
 	defaultTraefikServiceNameNamePattern             = "{project}-{group}-{app}-8080@kubernetes"
+	defaultNginxIngressNamePattern                   = "{group}-{app}"
+	defaultIstioServiceNamePattern                   = "{group}-{app}"
+	defaultAwsLbTargetGroupNamePattern               = ""
+	defaultAwsLbIngressStackNamePattern              = "{project}/{group}-{app}"
+	defaultAwsLbClusterNamePattern                   = ""
 	propCloudwatchNamespace                          = "cloudwatch_namespace"
 	propEcsCluster                                   = "ecs_cluster"
 	propEcsService                                   = "ecs_service"
@@ -39,12 +44,27 @@ const (
 	propKubernetesNamespace                          = "kubernetes_namespace"
 	propKubernetesPod                                = "kubernetes_pod"
 	propTraefikServiceName                           = "traefik_service_name"
+	propNginxIngress                                 = "nginx_ingress"
+	propIstioService                                 = "istio_service"
+	propAwsLbTargetGroup                             = "aws_lb_target_group"
+	propAwsLbIngressStack                            = "aws_lb_ingress_stack"
+	propAwsLbCluster                                 = "aws_lb_cluster"
 )
 
 type providerData struct {
+	Aws          types.Object `tfsdk:"aws"`
+	Kubernetes   types.Object `tfsdk:"kubernetes"`
 	Metadata     types.Object `tfsdk:"metadata"`
 	NamePatterns types.Object `tfsdk:"name_patterns"`
 	Orchestrator types.String `tfsdk:"orchestrator"`
+	Ingress      types.String `tfsdk:"ingress"`
+	Theme        types.Object `tfsdk:"theme"`
+	Thresholds   types.Object `tfsdk:"thresholds"`
+}
+
+type panelThresholdData struct {
+	Warning  types.Int64 `tfsdk:"warning"`
+	Critical types.Int64 `tfsdk:"critical"`
 }
 
 type ResourceNamePatterns struct {
@@ -57,6 +77,42 @@ type ResourceNamePatterns struct {
 	KubernetesNamespace            string
 	KubernetesPod                  string
 	TraefikServiceName             string
+	NginxIngress                   string
+	IstioService                   string
+	AwsLbTargetGroup               string
+	AwsLbIngressStack              string
+	AwsLbCluster                   string
+}
+
+type awsData struct {
+	Region                 types.String `tfsdk:"region"`
+	Profile                types.String `tfsdk:"profile"`
+	SharedConfigFiles      types.List   `tfsdk:"shared_config_files"`
+	SharedCredentialsFiles types.List   `tfsdk:"shared_credentials_files"`
+	AssumeRole             types.Object `tfsdk:"assume_role"`
+	Endpoints              types.Object `tfsdk:"endpoints"`
+}
+
+type awsAssumeRoleData struct {
+	RoleArn     types.String `tfsdk:"role_arn"`
+	ExternalId  types.String `tfsdk:"external_id"`
+	SessionName types.String `tfsdk:"session_name"`
+}
+
+type awsEndpointsData struct {
+	Ecs   types.String `tfsdk:"ecs"`
+	Elbv2 types.String `tfsdk:"elbv2"`
+}
+
+type kubernetesData struct {
+	ConfigPath           types.String `tfsdk:"config_path"`
+	ConfigContext        types.String `tfsdk:"config_context"`
+	InCluster            types.Bool   `tfsdk:"in_cluster"`
+	Host                 types.String `tfsdk:"host"`
+	Token                types.String `tfsdk:"token"`
+	ClusterCaCertificate types.String `tfsdk:"cluster_ca_certificate"`
+	Insecure             types.Bool   `tfsdk:"insecure"`
+	SelectPodsByLabels   types.Bool   `tfsdk:"select_pods_by_labels"`
 }
 
 type MetadataProperties struct {
@@ -66,10 +122,16 @@ type MetadataProperties struct {
 }
 
 type GosolineProvider struct {
+	awsClients                    *builder.AwsClients
+	kubernetesClient              *builder.KubernetesClient
+	kubernetesSelectPodsByLabels  bool
 	metadataReader                *builder.MetadataReader
 	resourceNamePatterns          ResourceNamePatterns
 	additionalAugmentReplacements map[string]string
 	orchestrator                  string
+	ingress                       string
+	theme                         builder.Theme
+	thresholds                    builder.Thresholds
 }
 
 func NewProvider() tfsdk.Provider {
@@ -77,8 +139,128 @@ func NewProvider() tfsdk.Provider {
 }
 
 func (p *GosolineProvider) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	themeAttributes := map[string]tfsdk.Attribute{}
+	for _, role := range builder.AvailableThemeRoles() {
+		themeAttributes[role] = tfsdk.Attribute{Type: types.StringType, Optional: true}
+	}
+
+	thresholdAttributes := map[string]tfsdk.Attribute{}
+	for _, kind := range builder.AvailablePanelKinds() {
+		thresholdAttributes[kind] = tfsdk.Attribute{
+			Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+				"warning":  {Type: types.Int64Type, Optional: true},
+				"critical": {Type: types.Int64Type, Optional: true},
+			}),
+			Optional: true,
+		}
+	}
+
 	return tfsdk.Schema{
 		Attributes: map[string]tfsdk.Attribute{
+			"aws": {
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"region": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"profile": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"shared_config_files": {
+						Type:     types.ListType{ElemType: types.StringType},
+						Optional: true,
+					},
+					"shared_credentials_files": {
+						Type:     types.ListType{ElemType: types.StringType},
+						Optional: true,
+					},
+					"assume_role": {
+						Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+							"role_arn": {
+								Type:     types.StringType,
+								Required: true,
+							},
+							"external_id": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+							"session_name": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+						}),
+						Optional: true,
+					},
+					"endpoints": {
+						Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+							"ecs": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+							"elbv2": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+						}),
+						Optional: true,
+					},
+				}),
+				Optional: true,
+				MarkdownDescription: `region: The AWS region used for the ECS and ELB lookups (default: resolved from the environment)
+									  profile: The shared config profile to use
+									  shared_config_files: List of shared config files to load instead of the default ones
+									  shared_credentials_files: List of shared credentials files to load instead of the default ones
+									  assume_role: Assume the given role_arn (optionally with external_id and session_name) before calling AWS
+									  endpoints: Custom endpoints for the ecs and elbv2 APIs, e.g. to test against a local stand-in`,
+			},
+			"kubernetes": {
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"config_path": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"config_context": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"in_cluster": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"host": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"token": {
+						Type:      types.StringType,
+						Optional:  true,
+						Sensitive: true,
+					},
+					"cluster_ca_certificate": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"insecure": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"select_pods_by_labels": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+				}),
+				Optional: true,
+				MarkdownDescription: `If set, the kubernetes orchestrator looks up the workload (deployment, statefulset, daemonset or argo rollout), its containers and its traefik service from the kubernetes api instead of relying on the name patterns only
+									  config_path: Path of the kubeconfig file to use (default: the files of KUBECONFIG or ~/.kube/config, the credential plugins of the kubeconfig are supported)
+									  config_context: Context of the kubeconfig to use (default: the current context of the kubeconfig)
+									  in_cluster: Use the service account of the pod the provider is running in
+									  host: Address of the kubernetes api server, overrides the one of the kubeconfig
+									  token: Bearer token to authenticate with, overrides the credentials of the kubeconfig
+									  cluster_ca_certificate: PEM encoded ca certificate of the api server
+									  insecure: Skip the verification of the api server certificate
+									  select_pods_by_labels: Select the pods by the selector of the workload through the kube_pod_labels metric instead of their name, kube-state-metrics has to expose the selector labels (--metric-labels-allowlist) (default: false)`,
+			},
 			"metadata": {
 				Type: types.ObjectType{
 					AttrTypes: map[string]attr.Type{
@@ -95,22 +277,44 @@ func (p *GosolineProvider) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diag
 			"orchestrator": {
 				Type:                types.StringType,
 				Optional:            true,
-				MarkdownDescription: `orchestrator: Set this to "ecs" for getting ELB/Target-group/ECS related metrics or "kubernetes" to get traefik related metrics inside the grafana dashboard`,
+				MarkdownDescription: `orchestrator: Set this to "ecs" for getting ELB/Target-group/ECS related metrics, "ecs_fargate" to additionally take the resource usage from container insights instead of cadvisor or "kubernetes" to get the metrics of the ingress inside the grafana dashboard`,
+			},
+			"ingress": {
+				Type:                types.StringType,
+				Optional:            true,
+				MarkdownDescription: `ingress: The ingress in front of the pods if the orchestrator is "kubernetes": "traefik", "nginx" (ingress-nginx), "istio" or "aws_lb_controller" (AWS Load Balancer Controller) (default: ` + defaultIngress + `)`,
+			},
+			"theme": {
+				Attributes: tfsdk.SingleNestedAttributes(themeAttributes),
+				Optional:   true,
+				MarkdownDescription: fmt.Sprintf(`Allows to change the grafana colors of the series of the dashboards by their role, roles which aren't set keep their default color
+									  Available roles are: %v`, builder.AvailableThemeRoles()),
+			},
+			"thresholds": {
+				Attributes: tfsdk.SingleNestedAttributes(thresholdAttributes),
+				Optional:   true,
+				MarkdownDescription: fmt.Sprintf(`Allows to change the thresholds of the panels of the dashboards, which are drawn as lines into the panels
+									  Every panel kind has a warning and a critical value, unset values keep their default and 0 disables the threshold
+									  Time series only draw the thresholds of configured panel kinds, besides the service utilization
+									  Available panel kinds are: %v`, builder.AvailablePanelKinds()),
 			},
 			"name_patterns": {
-				Type: types.ObjectType{
-					AttrTypes: map[string]attr.Type{
-						propHostname:                       types.StringType,
-						propCloudwatchNamespace:            types.StringType,
-						propEcsCluster:                     types.StringType,
-						propEcsService:                     types.StringType,
-						propGrafanaCloudwatchDatasource:    types.StringType,
-						propGrafanaElasticsearchDatasource: types.StringType,
-						propKubernetesNamespace:            types.StringType,
-						propKubernetesPod:                  types.StringType,
-						propTraefikServiceName:             types.StringType,
-					},
-				},
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					propHostname:                       {Type: types.StringType, Optional: true},
+					propCloudwatchNamespace:            {Type: types.StringType, Optional: true},
+					propEcsCluster:                     {Type: types.StringType, Optional: true},
+					propEcsService:                     {Type: types.StringType, Optional: true},
+					propGrafanaCloudwatchDatasource:    {Type: types.StringType, Optional: true},
+					propGrafanaElasticsearchDatasource: {Type: types.StringType, Optional: true},
+					propKubernetesNamespace:            {Type: types.StringType, Optional: true},
+					propKubernetesPod:                  {Type: types.StringType, Optional: true},
+					propTraefikServiceName:             {Type: types.StringType, Optional: true},
+					propNginxIngress:                   {Type: types.StringType, Optional: true},
+					propIstioService:                   {Type: types.StringType, Optional: true},
+					propAwsLbTargetGroup:               {Type: types.StringType, Optional: true},
+					propAwsLbIngressStack:              {Type: types.StringType, Optional: true},
+					propAwsLbCluster:                   {Type: types.StringType, Optional: true},
+				}),
 				Optional: true,
 				MarkdownDescription: `hostname: Allows to change the default metadata hostname name pattern (default: ` + defaultMetadataHostnameNamePattern + `)
 										  Available placeholders are:
@@ -172,6 +376,41 @@ func (p *GosolineProvider) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diag
 										  * {group}
 										  * {app}
 									  traefik_service_name: Allows to change the default traefik service name pattern (default: ` + defaultTraefikServiceNameNamePattern + `)
+										  Available placeholders are:
+										  * {project}
+										  * {env}
+										  * {family}
+										  * {group}
+										  * {app}
+									  nginx_ingress: Allows to change the default name pattern of the ingress resource used with the nginx ingress (default: ` + defaultNginxIngressNamePattern + `)
+										  Available placeholders are:
+										  * {project}
+										  * {env}
+										  * {family}
+										  * {group}
+										  * {app}
+									  istio_service: Allows to change the default name pattern of the destination service used with the istio ingress (default: ` + defaultIstioServiceNamePattern + `)
+										  Available placeholders are:
+										  * {project}
+										  * {env}
+										  * {family}
+										  * {group}
+										  * {app}
+									  aws_lb_target_group: Allows to look up the target group used with the aws_lb_controller ingress by its name, e.g. for target groups bound through a TargetGroupBinding. If empty, the target groups are looked up by the tags of the aws load balancer controller (default: empty)
+										  Available placeholders are:
+										  * {project}
+										  * {env}
+										  * {family}
+										  * {group}
+										  * {app}
+									  aws_lb_ingress_stack: Allows to change the default pattern of the ingress.k8s.aws/stack tag of the target groups used with the aws_lb_controller ingress, it is either <namespace>/<ingress> or the name of the ingress group (default: ` + defaultAwsLbIngressStackNamePattern + `)
+										  Available placeholders are:
+										  * {project}
+										  * {env}
+										  * {family}
+										  * {group}
+										  * {app}
+									  aws_lb_cluster: Allows to restrict the target groups used with the aws_lb_controller ingress to the ones with a matching elbv2.k8s.aws/cluster tag (default: empty, the cluster isn't checked)
 										  Available placeholders are:
 										  * {project}
 										  * {env}
@@ -206,6 +445,28 @@ func (p *GosolineProvider) Configure(ctx context.Context, request tfsdk.Configur
 		return
 	}
 
+	awsSettings, err := p.getAwsSettings(ctx, config)
+	if err != nil {
+		response.Diagnostics.AddError("failed to get aws settings from attributes", err.Error())
+
+		return
+	}
+
+	kubernetesSettings, err := p.getKubernetesSettings(ctx, config)
+	if err != nil {
+		response.Diagnostics.AddError("failed to get kubernetes settings from attributes", err.Error())
+
+		return
+	}
+
+	if kubernetesSettings != nil {
+		if p.kubernetesClient, err = builder.NewKubernetesClient(*kubernetesSettings); err != nil {
+			response.Diagnostics.AddError("can not create kubernetes client", err.Error())
+
+			return
+		}
+	}
+
 	scheme := "https"
 	if !metadataProperties.UseHttps {
 		scheme = "http"
@@ -223,12 +484,35 @@ func (p *GosolineProvider) Configure(ctx context.Context, request tfsdk.Configur
 	if !config.Orchestrator.IsNull() {
 		p.orchestrator = config.Orchestrator.Value
 	}
-	if !funk.ContainsString(availableOrchestrators, p.orchestrator) {
+	if availableOrchestrators := builder.AvailableOrchestrators(); !funk.ContainsString(availableOrchestrators, p.orchestrator) {
 		response.Diagnostics.AddError("invalid operator", fmt.Sprintf("'%s' is not a valid orchestrator, choose between %v", p.orchestrator, availableOrchestrators))
 
 		return
 	}
 
+	p.ingress = defaultIngress
+	if !config.Ingress.IsNull() {
+		p.ingress = config.Ingress.Value
+	}
+	if availableIngresses := builder.AvailableIngresses(); !funk.ContainsString(availableIngresses, p.ingress) {
+		response.Diagnostics.AddError("invalid ingress", fmt.Sprintf("'%s' is not a valid ingress, choose between %v", p.ingress, availableIngresses))
+
+		return
+	}
+
+	if p.theme, err = p.getTheme(ctx, config); err != nil {
+		response.Diagnostics.AddError("invalid theme", err.Error())
+
+		return
+	}
+
+	if p.thresholds, err = p.getThresholds(ctx, config); err != nil {
+		response.Diagnostics.AddError("invalid thresholds", err.Error())
+
+		return
+	}
+
+	p.awsClients = builder.NewAwsClients(*awsSettings)
 	p.resourceNamePatterns = *namepatternProperties
 	p.metadataReader = builder.NewMetadataReader(namepatternProperties.Hostname, additionalReplacements)
 }
@@ -239,8 +523,10 @@ func (p *GosolineProvider) GetResources(_ context.Context) (map[string]tfsdk.Res
 
 func (p *GosolineProvider) GetDataSources(_ context.Context) (map[string]tfsdk.DataSourceType, diag.Diagnostics) {
 	return map[string]tfsdk.DataSourceType{
+		"gosoline_application_alarm_definitions":    &ApplicationAlarmDefinitionsDatasourceType{},
 		"gosoline_application_dashboard_definition": &ApplicationDashboardDefinitionDatasourceType{},
 		"gosoline_application_metadata_definition":  &ApplicationMetadataDefinitionDatasourceType{},
+		"gosoline_application_prometheus_rules":     &ApplicationPrometheusRulesDatasourceType{},
 	}, nil
 }
 
@@ -338,10 +624,15 @@ func (p *GosolineProvider) getNamepatternProperties(ctx context.Context, config
 		propKubernetesNamespace:            defaultKubernetesNamespaceNamePattern,
 		propKubernetesPod:                  defaultKubernetesPodNamePattern,
 		propTraefikServiceName:             defaultTraefikServiceNameNamePattern,
+		propNginxIngress:                   defaultNginxIngressNamePattern,
+		propIstioService:                   defaultIstioServiceNamePattern,
+		propAwsLbTargetGroup:               defaultAwsLbTargetGroupNamePattern,
+		propAwsLbIngressStack:              defaultAwsLbIngressStackNamePattern,
+		propAwsLbCluster:                   defaultAwsLbClusterNamePattern,
 	}
 
 	for key := range patterns {
-		if _, ok := config.NamePatterns.Attrs[key]; !ok {
+		if value, ok := config.NamePatterns.Attrs[key]; !ok || value.IsNull() {
 			continue
 		}
 
@@ -363,7 +654,139 @@ func (p *GosolineProvider) getNamepatternProperties(ctx context.Context, config
 		KubernetesNamespace:            patterns[propKubernetesNamespace],
 		KubernetesPod:                  patterns[propKubernetesPod],
 		TraefikServiceName:             patterns[propTraefikServiceName],
+		NginxIngress:                   patterns[propNginxIngress],
+		IstioService:                   patterns[propIstioService],
+		AwsLbTargetGroup:               patterns[propAwsLbTargetGroup],
+		AwsLbIngressStack:              patterns[propAwsLbIngressStack],
+		AwsLbCluster:                   patterns[propAwsLbCluster],
 	}
 
 	return props, nil
 }
+
+func (p *GosolineProvider) getAwsSettings(ctx context.Context, config providerData) (*builder.AwsSettings, error) {
+	settings := &builder.AwsSettings{}
+
+	if config.Aws.IsNull() {
+		return settings, nil
+	}
+
+	var data awsData
+	if diags := config.Aws.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert aws attribute to native type: %v", diags)
+	}
+
+	settings.Region = data.Region.Value
+	settings.Profile = data.Profile.Value
+
+	if diags := data.SharedConfigFiles.ElementsAs(ctx, &settings.SharedConfigFiles, false); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert aws.shared_config_files attribute to native type: %v", diags)
+	}
+
+	if diags := data.SharedCredentialsFiles.ElementsAs(ctx, &settings.SharedCredentialsFiles, false); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert aws.shared_credentials_files attribute to native type: %v", diags)
+	}
+
+	if !data.AssumeRole.IsNull() {
+		var assumeRole awsAssumeRoleData
+		if diags := data.AssumeRole.As(ctx, &assumeRole, types.ObjectAsOptions{}); diags.HasError() {
+			return nil, fmt.Errorf("failed to convert aws.assume_role attribute to native type: %v", diags)
+		}
+
+		settings.AssumeRole = &builder.AwsAssumeRoleSettings{
+			RoleArn:     assumeRole.RoleArn.Value,
+			ExternalId:  assumeRole.ExternalId.Value,
+			SessionName: assumeRole.SessionName.Value,
+		}
+	}
+
+	if !data.Endpoints.IsNull() {
+		var endpoints awsEndpointsData
+		if diags := data.Endpoints.As(ctx, &endpoints, types.ObjectAsOptions{}); diags.HasError() {
+			return nil, fmt.Errorf("failed to convert aws.endpoints attribute to native type: %v", diags)
+		}
+
+		settings.Endpoints = builder.AwsEndpointSettings{
+			Ecs:   endpoints.Ecs.Value,
+			Elbv2: endpoints.Elbv2.Value,
+		}
+	}
+
+	return settings, nil
+}
+
+func (p *GosolineProvider) getKubernetesSettings(ctx context.Context, config providerData) (*builder.KubernetesSettings, error) {
+	if config.Kubernetes.IsNull() {
+		return nil, nil
+	}
+
+	var data kubernetesData
+	if diags := config.Kubernetes.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert kubernetes attribute to native type: %v", diags)
+	}
+
+	settings := &builder.KubernetesSettings{
+		ConfigPath:           data.ConfigPath.Value,
+		ConfigContext:        data.ConfigContext.Value,
+		InCluster:            data.InCluster.Value,
+		Host:                 data.Host.Value,
+		Token:                data.Token.Value,
+		ClusterCaCertificate: data.ClusterCaCertificate.Value,
+		Insecure:             data.Insecure.Value,
+	}
+
+	// the selection of the pods isn't a setting of the client, it is passed on to the orchestrator
+	p.kubernetesSelectPodsByLabels = data.SelectPodsByLabels.Value
+
+	return settings, nil
+}
+
+func (p *GosolineProvider) getTheme(ctx context.Context, config providerData) (builder.Theme, error) {
+	colors := map[string]string{}
+
+	for key, value := range config.Theme.Attrs {
+		if value.IsNull() {
+			continue
+		}
+
+		var color string
+		if err := p.convertToNativeType(ctx, value, &color); err != nil {
+			return builder.Theme{}, fmt.Errorf("failed to convert theme.%s attribute to native type: %w", key, err)
+		}
+
+		colors[key] = color
+	}
+
+	return builder.NewTheme(colors)
+}
+
+func (p *GosolineProvider) getThresholds(ctx context.Context, config providerData) (builder.Thresholds, error) {
+	kinds := map[string]builder.PanelThreshold{}
+
+	for kind, value := range config.Thresholds.Attrs {
+		object, ok := value.(types.Object)
+		if !ok || object.IsNull() {
+			continue
+		}
+
+		var data panelThresholdData
+		if diags := object.As(ctx, &data, types.ObjectAsOptions{}); diags.HasError() {
+			return builder.Thresholds{}, fmt.Errorf("failed to convert thresholds.%s attribute to native type: %v", kind, diags)
+		}
+
+		// the zero value of the thresholds hands out the defaults for the values which aren't set
+		threshold := builder.Thresholds{}.Get(kind)
+
+		if !data.Warning.IsNull() {
+			threshold.Warning = int(data.Warning.Value)
+		}
+
+		if !data.Critical.IsNull() {
+			threshold.Critical = int(data.Critical.Value)
+		}
+
+		kinds[kind] = threshold
+	}
+
+	return builder.NewThresholds(kinds)
+}